@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the hash cache",
+	Long:  `Manage the hashes cached in the history database`,
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale cached hashes",
+	Long:  `Remove cached hashes for files that have changed or no longer exist`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		pruned, err := hash.PruneCache()
+		db.CloseDB()
+		if err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		pterm.Success.Printfln("Removed %d cached hash(es)", pruned)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+}
@@ -4,44 +4,71 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/flosch/pongo2/v4"
 	"github.com/jhotmann/go-fileutils-cli/lib/operation"
 	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
 
 	"github.com/spf13/cobra"
 )
 
 var cpCmd = &cobra.Command{
-	Use:     "cp {file(s) to copy} {output template}",
+	Use:     "cp {file(s) to copy} {output template}|--to {output template}...",
 	Short:   "Copy files",
 	Long:    `Copy files with the power of templates`,
-	Args:    cobra.MinimumNArgs(2),
+	Args:    outputArgs,
 	Aliases: []string{"copy"},
 
 	Run: func(cmd *cobra.Command, args []string) {
-		// output is the last non-flag argument
-		outputTemplate, err := pongo2.FromString(args[len(args)-1])
+		// parse options into our own struct
+		opts := options.GetCommonOptions(cmd)
+		if err := options.ValidatePortable(opts.Portable); err != nil {
+			fmt.Println("Invalid Portable: ", err.Error())
+			os.Exit(1)
+		}
+		if err := options.ValidateNormalize(opts.Normalize); err != nil {
+			fmt.Println("Invalid Normalize: ", err.Error())
+			os.Exit(1)
+		}
+		// output is the last non-flag argument or every --to template, all others are input files
+		inputFiles, outputTemplates, err := parseOutputTemplates(args, opts.To)
 		if err != nil {
 			fmt.Println("Invalid Output: ", err.Error())
 			os.Exit(1)
 		}
-		// all other non-flag arguments are input files
-		inputFiles := args[0 : len(args)-1]
-		// parse options into our own struct
-		opts := options.GetCommonOptions(cmd)
+		// dates are rendered in the --tz time zone, make sure it exists
+		if _, err := util.LoadTimeZone(opts.TimeZone); err != nil {
+			fmt.Println("Invalid Time Zone: ", err.Error())
+			os.Exit(1)
+		}
 		// create a list of operations for all input files
-		operations := operation.FilesToOperationsList("copy", inputFiles, outputTemplate)
+		operations := operation.FilesToOperationsList("copy", inputFiles, opts, outputTemplates...)
 		// filter out directories if --ignore-directories option passed
 		if opts.IgnoreDirectories {
 			operations = operations.RemoveDirectories()
 		}
+		// filter out inputs that don't match the --where expression
+		operations, err = operations.Where(opts.Where, opts)
+		if err != nil {
+			fmt.Println("Invalid Where: ", err.Error())
+			os.Exit(1)
+		}
 		// filter out repeat inputs (only applies to moves), sort, and convert output from template to string to PathObj
-		operations = operations.RemoveDuplicateInputs().Sort(opts.Sort).RenderTemplates()
+		operations, err = operations.RemoveDuplicateInputs().Sort(opts.Sort).RenderTemplates(opts)
+		if err != nil {
+			fmt.Println("Invalid Template: ", err.Error())
+			os.Exit(1)
+		}
 		if !opts.NoExt {
 			operations = operations.PopulateBlankExtensions()
 		}
+		if opts.Normalize != "" { // normalize output names to NFC/NFD
+			operations = operations.Normalize(opts.Normalize)
+		}
+		if opts.Portable != "" { // clean output names that aren't valid on the chosen platform
+			operations = operations.Portable(opts.Portable, opts)
+		}
 		if !opts.Force { // don't care about conflicts
-			operations = operations.FindConflicts()
+			operations = operations.FindConflicts(opts)
 		}
 		if !opts.NoIndex { // auto-index conflicting outputs
 			operations = operations.AddIndex()
@@ -61,4 +88,10 @@ func init() {
 	cpCmd.Flags().Bool("no-move", options.NoMove, "Do not move files to a different directory")
 	cpCmd.Flags().Bool("no-ext", options.NoExt, "Do not automatically append the original file extension if one isn't supplied")
 	cpCmd.Flags().Bool("no-mkdir", options.NoMkdir, "Do not create any missing directories")
+	cpCmd.Flags().String("where", options.Where, "Only operate on inputs where this template expression is true")
+	cpCmd.Flags().String("portable", options.Portable, "Clean output names so they are valid on a platform (posix, windows, macos)")
+	cpCmd.Flags().String("normalize", options.Normalize, "Normalize output names and compare names in a unicode normalization form (nfc, nfd)")
+	cpCmd.Flags().String("tz", options.TimeZone, "Time zone to render dates in, such as America/New_York or UTC")
+	cpCmd.Flags().Bool("verify", options.Verify, "Re-read copies and compare their hashes to the inputs, retrying copies that don't match")
+	cpCmd.Flags().StringArray("to", options.To, "Output template, repeat to write each input to multiple outputs")
 }
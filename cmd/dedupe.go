@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/dedupe"
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe {file(s) or directories}",
+	Short: "Find and resolve duplicate files",
+	Long: `Find files with identical contents and, with --action, delete the duplicates or replace them with links
+to the file that is kept. Resolved duplicates can be restored with fu undo.`,
+	Args: cobra.MinimumNArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetDedupeOptions(cmd)
+		if err := options.ValidateStrategy(opts.Strategy); err != nil {
+			pterm.Error.WithShowLineNumber(false).Println("Invalid Strategy: " + err.Error())
+			os.Exit(1)
+		}
+		groups, problems := dedupe.Find(hash.ExpandPaths(args, opts.Recursive), !opts.NoCache)
+		for _, problem := range problems {
+			pterm.Warning.Println(problem.Error())
+		}
+		if len(groups) == 0 {
+			db.CloseDB()
+			pterm.Info.Println("No duplicate files found")
+			return
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(groups.ToTableData(opts.Strategy, opts.Keep, opts.Action)).Render()
+		pterm.Println()
+		pterm.Info.Printfln("%d group(s) of duplicates using %s", len(groups), util.FormatBytes(groups.Wasted(), false))
+		if opts.Action == "" || opts.Simulate {
+			db.CloseDB()
+			return
+		}
+		if !opts.Force {
+			prompt := promptui.Prompt{
+				Label:     "Are you sure you want to " + opts.Action + " the duplicates?",
+				IsConfirm: true,
+			}
+			result, _ := prompt.Run()
+			if util.IndexOf(strings.ToLower(result), []string{"y", "yes", "true", "1"}) == -1 {
+				db.CloseDB()
+				return
+			}
+		}
+		batch := db.NewBatch("dedupe", os.Args[1:], util.GetWorkingDir())
+		defer batch.Close()
+		resolved := 0
+		for _, group := range groups {
+			kept, duplicates := group.Keep(opts.Strategy, opts.Keep)
+			keptAbs, _ := filepath.Abs(kept.Path)
+			for _, duplicate := range duplicates {
+				if err := dedupe.Resolve(kept, duplicate, opts.Action); err != nil {
+					pterm.Error.WithShowLineNumber(false).Println(err.Error())
+					continue
+				}
+				duplicateAbs, _ := filepath.Abs(duplicate.Path)
+				db.WriteDedupeOperation(batch.Id, keptAbs, duplicateAbs, duplicate.Stats, hash.CompareAlgorithm, group.Digest)
+				resolved++
+			}
+		}
+		pterm.Success.Printfln("Resolved %d duplicate(s)", resolved)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dedupeCmd)
+	dedupeCmd.Flags().BoolP("recursive", "r", options.Recursive, "Search the files inside directories")
+	dedupeCmd.Flags().String("strategy", options.Strategy, "Which file in each group to keep (oldest, newest, shortest)")
+	dedupeCmd.Flags().String("keep", options.Keep, "Keep files whose path or name matches this glob, the strategy picks between several matches")
+	dedupeCmd.Flags().String("action", options.Action, "What to do with duplicates (delete, hardlink, symlink), duplicates are only listed without it")
+	dedupeCmd.Flags().BoolP("force", "f", options.Force, "Resolve duplicates without prompt")
+	dedupeCmd.Flags().BoolP("simulate", "s", options.Simulate, "Only list duplicates and what would happen to them")
+	dedupeCmd.Flags().Bool("no-cache", options.NoCache, "Always read files instead of reusing cached hashes")
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/operation"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+var formatEscapes = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+
+var findCmd = &cobra.Command{
+	Use:   "find {file(s) to list}",
+	Short: "List files with templates",
+	Long:  `Print a line rendered from the --format template for every matching file, templates have the same variables and filters as mv, cp and ln`,
+	Args:  cobra.MinimumNArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetFindOptions(cmd)
+		if err := options.ValidateNormalize(opts.Normalize); err != nil {
+			fmt.Println("Invalid Normalize: ", err.Error())
+			os.Exit(1)
+		}
+		// allow tabs and newlines to be typed like find -printf
+		format, err := snippets.FromString(formatEscapes.Replace(opts.Format))
+		if err != nil {
+			fmt.Println("Invalid Format: ", err.Error())
+			os.Exit(1)
+		}
+		// dates are rendered in the --tz time zone, make sure it exists
+		if _, err := util.LoadTimeZone(opts.TimeZone); err != nil {
+			fmt.Println("Invalid Time Zone: ", err.Error())
+			os.Exit(1)
+		}
+		operations := operation.FilesToOperationsList("find", args, opts.CommonOptions, format)
+		if opts.Recursive {
+			operations = operations.Recurse()
+		}
+		if opts.IgnoreDirectories {
+			operations = operations.RemoveDirectories()
+		}
+		operations, err = operations.Where(opts.Where, opts.CommonOptions)
+		if err != nil {
+			fmt.Println("Invalid Where: ", err.Error())
+			os.Exit(1)
+		}
+		lines, err := operations.RemoveDuplicateInputs().Sort(opts.Sort).Format(opts.CommonOptions)
+		db.CloseDB() // the hash function may have opened the cache
+		if err != nil {
+			fmt.Println("Invalid Format: ", err.Error())
+			os.Exit(1)
+		}
+		separator := "\n"
+		if opts.Null {
+			separator = "\x00"
+		}
+		for _, line := range lines {
+			fmt.Print(line + separator)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+	findCmd.Flags().String("format", options.Format, "Template to render for each file")
+	findCmd.Flags().BoolP("null", "0", options.Null, "Separate lines with NUL characters instead of newlines, for xargs -0")
+	findCmd.Flags().BoolP("recursive", "r", options.Recursive, "List the contents of directories too")
+	findCmd.Flags().String("sort", options.Sort, "Sort files before listing them")
+	findCmd.Flags().BoolP("verbose", "v", options.Verbose, "Verbose logging")
+	findCmd.Flags().BoolP("ignore-directories", "d", options.IgnoreDirectories, "Do not list directories")
+	findCmd.Flags().String("where", options.Where, "Only list files where this template expression is true")
+	findCmd.Flags().String("normalize", options.Normalize, "Compare names in a unicode normalization form (nfc, nfd)")
+	findCmd.Flags().String("tz", options.TimeZone, "Time zone to render dates in, such as America/New_York or UTC")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+)
+
+var hashCmd = &cobra.Command{
+	Use:   "hash {file(s) to hash}|--check {checksum file(s)}",
+	Short: "Hash files",
+	Long:  `Get the hash of one or more files, use an algorithm's name as an alias (md5, sha256, xxh3, blake3, etc.) or --algo to pick the algorithm`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if benchmark, _ := cmd.Flags().GetBool("benchmark"); benchmark {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	Aliases: []string{"md5", "sha1", "sha256", "sha512", "crc32", "xxh64", "xxh3", "blake2b", "blake3"},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetHashOptions(cmd)
+		if opts.Benchmark {
+			pterm.DefaultTable.WithHasHeader().WithData(hash.Benchmark(256 << 20).ToTableData()).Render()
+			return
+		}
+		algo, err := hash.GetAlgorithm(opts.Algorithm)
+		if err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		if opts.Check {
+			checkHashes(args, opts, algo)
+			return
+		}
+		results := hash.Files(hash.ExpandPaths(args, opts.Recursive), algo, !opts.NoCache)
+		db.CloseDB()
+		if opts.JSON {
+			out, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			for _, result := range results {
+				if result.Error != "" {
+					pterm.Error.WithShowLineNumber(false).Println(result.Error)
+					continue
+				}
+				fmt.Println(result.String(opts.BSD))
+			}
+		}
+		if results.HasErrors() {
+			os.Exit(1)
+		}
+	},
+}
+
+// checkHashes verifies the files listed in checksum files and exits with 1 when any don't match or are missing
+func checkHashes(sumFiles []string, opts options.HashOptions, algo hash.Algorithm) {
+	var forced *hash.Algorithm
+	if opts.AlgorithmForced {
+		forced = &algo
+	}
+	entries := []hash.CheckEntry{}
+	for _, f := range sumFiles {
+		fileEntries, problems := hash.ParseChecksumFile(f, forced)
+		for _, problem := range problems {
+			pterm.Warning.Println(problem.Error())
+		}
+		entries = append(entries, fileEntries...)
+	}
+	if len(entries) == 0 {
+		pterm.Error.WithShowLineNumber(false).Println("No properly formatted checksum lines found")
+		os.Exit(1)
+	}
+	results := hash.Check(entries, !opts.NoCache)
+	db.CloseDB()
+	if opts.JSON {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(out))
+	} else {
+		for _, result := range results {
+			if opts.Quiet && result.Status == hash.StatusOK {
+				continue
+			}
+			fmt.Printf("%s: %s\n", result.Path, result.Status)
+		}
+		if failed := results.Count(hash.StatusFailed); failed > 0 && !opts.Quiet {
+			pterm.Warning.Printfln("%d computed checksum(s) did NOT match", failed)
+		}
+		if missing := results.Count(hash.StatusMissing); missing > 0 && !opts.Quiet {
+			pterm.Warning.Printfln("%d listed file(s) could not be found", missing)
+		}
+	}
+	if results.Count(hash.StatusOK) != len(results) {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(hashCmd)
+	hashCmd.Flags().StringP("algo", "a", options.Algorithm, "Hash algorithm to use")
+	hashCmd.Flags().BoolP("recursive", "r", options.Recursive, "Hash the files inside directories")
+	hashCmd.Flags().Bool("bsd", options.BSD, "Output BSD style lines, like ALGO (file) = hash")
+	hashCmd.Flags().Bool("json", options.JSON, "Output results as JSON")
+	hashCmd.Flags().BoolP("check", "c", options.Check, "Read checksums from the files and verify them")
+	hashCmd.Flags().BoolP("quiet", "q", options.Quiet, "When checking, only print files that fail")
+	hashCmd.Flags().Bool("no-cache", options.NoCache, "Always read files instead of reusing cached hashes")
+	hashCmd.Flags().Bool("benchmark", options.Benchmark, "Compare the speed of every hash algorithm")
+}
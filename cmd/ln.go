@@ -4,49 +4,76 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/flosch/pongo2/v4"
 	"github.com/jhotmann/go-fileutils-cli/lib/operation"
 	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
 
 	"github.com/spf13/cobra"
 )
 
 var lnCmd = &cobra.Command{
-	Use:     "ln {file(s) to link} {output template}",
+	Use:     "ln {file(s) to link} {output template}|--to {output template}...",
 	Short:   "Link files",
 	Long:    `Link files with the power of templates`,
-	Args:    cobra.MinimumNArgs(2),
+	Args:    outputArgs,
 	Aliases: []string{"link", "mklink"},
 
 	Run: func(cmd *cobra.Command, args []string) {
-		// output is the last non-flag argument
-		outputTemplate, err := pongo2.FromString(args[len(args)-1])
+		// parse options into our own struct
+		opts := options.GetLinkOptions(cmd)
+		if err := options.ValidatePortable(opts.Portable); err != nil {
+			fmt.Println("Invalid Portable: ", err.Error())
+			os.Exit(1)
+		}
+		if err := options.ValidateNormalize(opts.Normalize); err != nil {
+			fmt.Println("Invalid Normalize: ", err.Error())
+			os.Exit(1)
+		}
+		// output is the last non-flag argument or every --to template, all others are input files
+		inputFiles, outputTemplates, err := parseOutputTemplates(args, opts.To)
 		if err != nil {
 			fmt.Println("Invalid Output: ", err.Error())
 			os.Exit(1)
 		}
-		// all other non-flag arguments are input files
-		inputFiles := args[0 : len(args)-1]
-		// parse options into our own struct
-		opts := options.GetLinkOptions(cmd)
+		// dates are rendered in the --tz time zone, make sure it exists
+		if _, err := util.LoadTimeZone(opts.TimeZone); err != nil {
+			fmt.Println("Invalid Time Zone: ", err.Error())
+			os.Exit(1)
+		}
 		// create a list of operations for all input files
 		var operations operation.OperationList
 		if opts.Soft {
-			operations = operation.FilesToOperationsList("link-soft", inputFiles, outputTemplate)
+			operations = operation.FilesToOperationsList("link-soft", inputFiles, opts.CommonOptions, outputTemplates...)
 		} else {
-			operations = operation.FilesToOperationsList("link-hard", inputFiles, outputTemplate)
+			operations = operation.FilesToOperationsList("link-hard", inputFiles, opts.CommonOptions, outputTemplates...)
 		}
 		// filter out directories if --ignore-directories option passed
 		if opts.IgnoreDirectories {
 			operations = operations.RemoveDirectories()
 		}
+		// filter out inputs that don't match the --where expression
+		operations, err = operations.Where(opts.Where, opts.CommonOptions)
+		if err != nil {
+			fmt.Println("Invalid Where: ", err.Error())
+			os.Exit(1)
+		}
 		// filter out repeat inputs (only applies to moves), sort, and convert output from template to string to PathObj
-		operations = operations.RemoveDuplicateInputs().Sort(opts.Sort).RenderTemplates()
+		operations, err = operations.RemoveDuplicateInputs().Sort(opts.Sort).RenderTemplates(opts.CommonOptions)
+		if err != nil {
+			fmt.Println("Invalid Template: ", err.Error())
+			os.Exit(1)
+		}
 		if !opts.NoExt {
 			operations = operations.PopulateBlankExtensions()
 		}
+		if opts.Normalize != "" { // normalize output names to NFC/NFD
+			operations = operations.Normalize(opts.Normalize)
+		}
+		if opts.Portable != "" { // clean output names that aren't valid on the chosen platform
+			operations = operations.Portable(opts.Portable, opts.CommonOptions)
+		}
 		if !opts.Force { // don't care about conflicts
-			operations = operations.FindConflicts()
+			operations = operations.FindConflicts(opts.CommonOptions)
 		}
 		if !opts.NoIndex { // auto-index conflicting outputs
 			operations = operations.AddIndex()
@@ -67,4 +94,9 @@ func init() {
 	lnCmd.Flags().Bool("no-move", options.NoMove, "Do not move files to a different directory")
 	lnCmd.Flags().Bool("no-ext", options.NoExt, "Do not automatically append the original file extension if one isn't supplied")
 	lnCmd.Flags().Bool("no-mkdir", options.NoMkdir, "Do not create any missing directories")
+	lnCmd.Flags().String("where", options.Where, "Only operate on inputs where this template expression is true")
+	lnCmd.Flags().String("portable", options.Portable, "Clean output names so they are valid on a platform (posix, windows, macos)")
+	lnCmd.Flags().String("normalize", options.Normalize, "Normalize output names and compare names in a unicode normalization form (nfc, nfd)")
+	lnCmd.Flags().String("tz", options.TimeZone, "Time zone to render dates in, such as America/New_York or UTC")
+	lnCmd.Flags().StringArray("to", options.To, "Output template, repeat to link each input to multiple outputs")
 }
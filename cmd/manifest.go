@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Detect changes and bit rot in directories",
+	Long:  `Record the size, modified time and hash of every file in a directory and later verify the directory against that manifest`,
+}
+
+var manifestCreateCmd = &cobra.Command{
+	Use:   "create {directory}",
+	Short: "Write a manifest of a directory",
+	Long:  `Hash every file inside the directory and write the manifest to ` + hash.ManifestName + ` inside it (or --manifest)`,
+	Args:  cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetManifestOptions(cmd)
+		algo, err := hash.GetAlgorithm(opts.Algorithm)
+		if err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		manifestPath := getManifestPath(args[0], opts)
+		manifest, problems := hash.CreateManifest(args[0], manifestPath, algo)
+		for _, problem := range problems {
+			pterm.Warning.Println(problem.Error())
+		}
+		if err := manifest.Write(manifestPath); err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		pterm.Success.Printfln("Wrote %d file(s) to %s", len(manifest.Entries), manifestPath)
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var manifestVerifyCmd = &cobra.Command{
+	Use:   "verify {directory}",
+	Short: "Compare a directory to its manifest",
+	Long: `Report files that were changed, added, removed or moved since the manifest was created. Files whose content
+changed while their size and modified time did not are reported as corrupted.`,
+	Args: cobra.ExactArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetManifestOptions(cmd)
+		manifestPath := getManifestPath(args[0], opts)
+		manifest, err := hash.ReadManifest(manifestPath)
+		if err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		changes, err := hash.VerifyManifest(args[0], manifestPath, manifest)
+		if err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		if opts.JSON {
+			out, _ := json.MarshalIndent(changes, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			for _, change := range changes {
+				if opts.Quiet && change.Status == hash.StatusOK {
+					continue
+				}
+				fmt.Println(change.String())
+			}
+			if corrupted := changes.Count(hash.StatusCorrupted); corrupted > 0 {
+				pterm.Warning.Printfln("%d file(s) changed without their modified time changing, they may be corrupted", corrupted)
+			}
+		}
+		if changes.Count(hash.StatusOK) != len(changes) {
+			os.Exit(1)
+		}
+	},
+}
+
+// getManifestPath defaults to the manifest file inside the directory
+func getManifestPath(dir string, opts options.ManifestOptions) string {
+	if opts.Manifest != "" {
+		return opts.Manifest
+	}
+	return filepath.Join(dir, hash.ManifestName)
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+	manifestCmd.AddCommand(manifestCreateCmd)
+	manifestCmd.AddCommand(manifestVerifyCmd)
+	manifestCmd.PersistentFlags().StringP("manifest", "m", options.Manifest, "Manifest file to use instead of "+hash.ManifestName+" inside the directory")
+	manifestCreateCmd.Flags().StringP("algo", "a", hash.IntegrityAlgorithm, "Hash algorithm to use")
+	manifestVerifyCmd.Flags().Bool("json", options.JSON, "Output results as JSON")
+	manifestVerifyCmd.Flags().BoolP("quiet", "q", options.Quiet, "Only print files that aren't OK")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/create"
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+var mkdirCmd = &cobra.Command{
+	Use:   "mkdir {template} [names]",
+	Short: "Create directories",
+	Long: `Create directories from a template rendered once for every name, names come from arguments, --names or --range.
+Templates can use name, n (the position of the name starting at 1), total and vars.`,
+	Args: cobra.MinimumNArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		createPaths(cmd, args, "mkdir", create.Directory)
+	},
+}
+
+// createPaths renders the template in the first argument for every name and records each created path in history
+func createPaths(cmd *cobra.Command, args []string, batchType string, createFn func(string) ([]string, error)) {
+	opts := options.GetCreateOptions(cmd)
+	if err := options.ValidatePortable(opts.Portable); err != nil {
+		fmt.Println("Invalid Portable: ", err.Error())
+		os.Exit(1)
+	}
+	template, err := snippets.FromString(args[0])
+	if err != nil {
+		fmt.Println("Invalid Template: ", err.Error())
+		os.Exit(1)
+	}
+	if _, err := util.LoadTimeZone(opts.TimeZone); err != nil {
+		fmt.Println("Invalid Time Zone: ", err.Error())
+		os.Exit(1)
+	}
+	names, err := create.Names(args[1:], opts.NamesFile, opts.Range)
+	if err != nil {
+		fmt.Println("Invalid Names: ", err.Error())
+		os.Exit(1)
+	}
+	vars, err := create.Vars(opts.Vars)
+	if err != nil {
+		fmt.Println("Invalid Var: ", err.Error())
+		os.Exit(1)
+	}
+	paths, err := create.Render(template, names, vars, opts)
+	if err != nil {
+		fmt.Println("Invalid Template: ", err.Error())
+		os.Exit(1)
+	}
+	if len(paths) == 0 {
+		pterm.Warning.Println("Nothing to create")
+		return
+	}
+	if opts.Simulate {
+		for _, path := range paths {
+			pterm.Info.Println(path)
+		}
+		return
+	}
+	batch := db.NewBatch(batchType, os.Args[1:], util.GetWorkingDir())
+	defer batch.Close()
+	failed := false
+	for _, path := range paths {
+		created, err := createFn(path)
+		for _, c := range created {
+			abs, _ := filepath.Abs(c)
+			db.WriteOperation(batch.Id, "", abs, "", "")
+			if opts.Verbose {
+				pterm.Success.Printfln("Created %s", c)
+			}
+		}
+		if err != nil {
+			pterm.Error.Println(err.Error())
+			failed = true
+		}
+	}
+	if failed {
+		batch.Close()
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mkdirCmd)
+	addCreateFlags(mkdirCmd)
+}
+
+func addCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().String("names", options.NamesFile, "Read names from a file, one per line")
+	cmd.Flags().String("range", options.Range, "Add the numbers in a range, like 1..12, to the names")
+	cmd.Flags().StringArray("var", options.Vars, "Set a template variable, like --var client=acme, used as {{ vars.client }}")
+	cmd.Flags().String("portable", options.Portable, "Clean output names so they are valid on a platform (posix, windows, macos)")
+	cmd.Flags().String("tz", options.TimeZone, "Time zone to render dates in, such as America/New_York or UTC")
+	cmd.Flags().BoolP("simulate", "s", options.Simulate, "Simulate command and print outputs")
+	cmd.Flags().BoolP("verbose", "v", options.Verbose, "Verbose logging")
+}
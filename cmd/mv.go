@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/flosch/pongo2/v4"
 	"github.com/jhotmann/go-fileutils-cli/lib/operation"
 	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
 
 	"github.com/spf13/cobra"
 )
@@ -20,7 +21,7 @@ var mvCmd = &cobra.Command{
 
 	Run: func(cmd *cobra.Command, args []string) {
 		// output is the last non-flag argument
-		outputTemplate, err := pongo2.FromString(args[len(args)-1])
+		outputTemplate, err := snippets.FromString(args[len(args)-1])
 		if err != nil {
 			fmt.Println("Invalid Output: ", err.Error())
 			os.Exit(1)
@@ -29,12 +30,25 @@ var mvCmd = &cobra.Command{
 		inputFiles := args[0 : len(args)-1]
 		// parse options into our own struct
 		opts := options.GetMoveOptions(cmd)
+		if err := options.ValidatePortable(opts.Portable); err != nil {
+			fmt.Println("Invalid Portable: ", err.Error())
+			os.Exit(1)
+		}
+		if err := options.ValidateNormalize(opts.Normalize); err != nil {
+			fmt.Println("Invalid Normalize: ", err.Error())
+			os.Exit(1)
+		}
 		// if rename alias used, set --no-move automatically
 		if cmd.CalledAs() == "rename" {
 			opts.NoMove = true
 		}
+		// dates are rendered in the --tz time zone, make sure it exists
+		if _, err := util.LoadTimeZone(opts.TimeZone); err != nil {
+			fmt.Println("Invalid Time Zone: ", err.Error())
+			os.Exit(1)
+		}
 		// create a list of operations for all input files
-		operations := operation.FilesToOperationsList("move", inputFiles, outputTemplate)
+		operations := operation.FilesToOperationsList("move", inputFiles, opts.CommonOptions, outputTemplate)
 		if len(operations) == 0 {
 			fmt.Println("Error: no operations can be created from the input(s) specified")
 			os.Exit(1)
@@ -43,16 +57,32 @@ var mvCmd = &cobra.Command{
 		if opts.IgnoreDirectories {
 			operations = operations.RemoveDirectories()
 		}
+		// filter out inputs that don't match the --where expression
+		operations, err = operations.Where(opts.Where, opts.CommonOptions)
+		if err != nil {
+			fmt.Println("Invalid Where: ", err.Error())
+			os.Exit(1)
+		}
 		// filter out repeat inputs (only applies to moves), sort, and convert output from template to string to PathObj
-		operations = operations.RemoveDuplicateInputs().Sort(opts.Sort).RenderTemplates()
+		operations, err = operations.RemoveDuplicateInputs().Sort(opts.Sort).RenderTemplates(opts.CommonOptions)
+		if err != nil {
+			fmt.Println("Invalid Template: ", err.Error())
+			os.Exit(1)
+		}
 		if !opts.NoExt {
 			operations = operations.PopulateBlankExtensions()
 		}
 		if opts.NoMove { // keep output directory same as input
 			operations = operations.NoMove()
 		}
+		if opts.Normalize != "" { // normalize output names to NFC/NFD
+			operations = operations.Normalize(opts.Normalize)
+		}
+		if opts.Portable != "" { // clean output names that aren't valid on the chosen platform
+			operations = operations.Portable(opts.Portable, opts.CommonOptions)
+		}
 		if !opts.Force { // don't care about conflicts
-			operations = operations.FindConflicts()
+			operations = operations.FindConflicts(opts.CommonOptions)
 		}
 		if !opts.NoIndex { // auto-index conflicting outputs
 			operations = operations.AddIndex()
@@ -72,4 +102,9 @@ func init() {
 	mvCmd.Flags().Bool("no-move", options.NoMove, "Do not move files to a different directory")
 	mvCmd.Flags().Bool("no-ext", options.NoExt, "Do not automatically append the original file extension if one isn't supplied")
 	mvCmd.Flags().Bool("no-mkdir", options.NoMkdir, "Do not create any missing directories")
+	mvCmd.Flags().String("where", options.Where, "Only operate on inputs where this template expression is true")
+	mvCmd.Flags().String("portable", options.Portable, "Clean output names so they are valid on a platform (posix, windows, macos)")
+	mvCmd.Flags().String("normalize", options.Normalize, "Normalize output names and compare names in a unicode normalization form (nfc, nfd)")
+	mvCmd.Flags().String("tz", options.TimeZone, "Time zone to render dates in, such as America/New_York or UTC")
+	mvCmd.Flags().Bool("verify", options.Verify, "When moving to another file system, re-read copies and compare their hashes to the inputs before removing the inputs")
 }
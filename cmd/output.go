@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/flosch/pongo2/v4"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+)
+
+// outputArgs requires at least one input and, unless --to is used, an output template
+func outputArgs(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetStringArray("to")
+	if len(to) > 0 {
+		return cobra.MinimumNArgs(1)(cmd, args)
+	}
+	return cobra.MinimumNArgs(2)(cmd, args)
+}
+
+// parseOutputTemplates splits the non-flag arguments into input files and output templates
+func parseOutputTemplates(args []string, to []string) ([]string, []*pongo2.Template, error) {
+	var templates []*pongo2.Template
+	inputFiles := args
+	if len(to) == 0 { // output is the last non-flag argument
+		to = args[len(args)-1:]
+		inputFiles = args[0 : len(args)-1]
+	}
+	for _, t := range to {
+		outputTemplate, err := snippets.FromString(t)
+		if err != nil {
+			return nil, nil, err
+		}
+		templates = append(templates, outputTemplate)
+	}
+	if len(templates) == 0 {
+		return nil, nil, errors.New("no output template specified")
+	}
+	return inputFiles, templates, nil
+}
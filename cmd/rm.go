@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/trash"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+var rmCmd = &cobra.Command{
+	Use:     "rm {file(s) to remove}",
+	Short:   "Move files to the trash",
+	Long:    `Move files to the trash so they can be restored with fu undo, use --permanent to delete them instead`,
+	Args:    cobra.MinimumNArgs(1),
+	Aliases: []string{"remove", "trash"},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetRemoveOptions(cmd)
+		paths := removePaths(args, opts)
+		if len(paths) == 0 {
+			pterm.Warning.Println("Nothing to remove")
+			return
+		}
+		if opts.Simulate {
+			for _, path := range paths {
+				if opts.Permanent {
+					pterm.Info.Printfln("Delete %s", path)
+				} else {
+					pterm.Info.Printfln("Trash %s", path)
+				}
+			}
+			return
+		}
+		if opts.Permanent && !opts.Force {
+			prompt := promptui.Prompt{
+				Label:     "Are you sure you want to permanently delete " + pterm.Sprintf("%d", len(paths)) + " file(s)?",
+				IsConfirm: true,
+			}
+			result, _ := prompt.Run()
+			if util.IndexOf(strings.ToLower(result), []string{"y", "yes", "true", "1"}) == -1 {
+				return
+			}
+		}
+		batchType := "trash"
+		if opts.Permanent {
+			batchType = "delete"
+		}
+		batch := db.NewBatch(batchType, os.Args[1:], util.GetWorkingDir())
+		defer batch.Close()
+		failed := false
+		for _, path := range paths {
+			abs, _ := filepath.Abs(path)
+			if opts.Permanent {
+				if err := os.RemoveAll(path); err != nil {
+					pterm.Error.Println(err.Error())
+					failed = true
+					continue
+				}
+				db.WriteOperation(batch.Id, abs, "", "", "")
+				if opts.Verbose {
+					pterm.Success.Printfln("Deleted %s", path)
+				}
+				continue
+			}
+			trashed, err := trash.Trash(path)
+			if err != nil {
+				pterm.Error.Println(err.Error())
+				failed = true
+				continue
+			}
+			db.WriteOperation(batch.Id, abs, trashed, "", "")
+			if opts.Verbose {
+				pterm.Success.Printfln("%s → %s", path, trashed)
+			}
+		}
+		if failed {
+			batch.Close()
+			os.Exit(1)
+		}
+	},
+}
+
+// removePaths expands globs and leaves out directories unless --recursive was passed
+func removePaths(globs []string, opts options.RemoveOptions) []string {
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, g := range globs {
+		matches, err := util.Glob(g, "")
+		if err != nil {
+			pterm.Warning.Println(err.Error())
+			continue
+		}
+		if len(matches) == 0 {
+			pterm.Warning.Printfln("%s does not match any existing files", g)
+		}
+		for _, match := range matches {
+			abs, _ := filepath.Abs(match)
+			if seen[abs] {
+				continue
+			}
+			seen[abs] = true
+			stats, err := os.Lstat(match)
+			if err != nil {
+				pterm.Warning.Println(err.Error())
+				continue
+			}
+			if stats.IsDir() && !opts.Recursive {
+				pterm.Warning.Printfln("%s is a directory, use --recursive to remove it", match)
+				continue
+			}
+			paths = append(paths, match)
+		}
+	}
+	return paths
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+	rmCmd.Flags().BoolP("recursive", "r", options.Recursive, "Remove directories and their contents")
+	rmCmd.Flags().Bool("permanent", options.Permanent, "Delete files instead of moving them to the trash, they can't be restored")
+	rmCmd.Flags().BoolP("force", "f", options.Force, "Permanently delete files without prompt")
+	rmCmd.Flags().BoolP("simulate", "s", options.Simulate, "Simulate command and print what would be removed")
+	rmCmd.Flags().BoolP("verbose", "v", options.Verbose, "Verbose logging")
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+)
+
+var templateCmd = &cobra.Command{
+	Use:     "template",
+	Short:   "Manage reusable template snippets",
+	Long:    `Manage reusable template snippets loaded from ~/.fu/templates/*.tpl and the templates section of the config`,
+	Aliases: []string{"t", "templates"},
+}
+
+var templateListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List available template snippets",
+	Long:    `List template snippets that can be used with {% include "name" %} or {{ use("name") }}`,
+	Aliases: []string{"ls"},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		list := snippets.GetSnippets()
+		if len(list) == 0 {
+			pterm.Info.Println("No template snippets found")
+			return
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(list.ToTableData()).Render()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/create"
+)
+
+var touchCmd = &cobra.Command{
+	Use:   "touch {template} [names]",
+	Short: "Create empty files",
+	Long: `Create empty files (and their directories) from a template rendered once for every name, names come from arguments,
+--names or --range. Templates can use name, n (the position of the name starting at 1), total and vars.
+Existing files have their modified time updated.`,
+	Args: cobra.MinimumNArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		createPaths(cmd, args, "touch", create.File)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(touchCmd)
+	addCreateFlags(touchCmd)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/operation"
 	"github.com/jhotmann/go-fileutils-cli/lib/util"
 	"github.com/pterm/pterm"
 )
@@ -40,7 +41,7 @@ var undoCmd = &cobra.Command{
 		}
 		result, _ := prompt.Run()
 		if util.IndexOf(strings.ToLower(result), []string{"y", "yes", "true", "1"}) > -1 {
-			err = batch.Undo()
+			err = operation.UndoBatch(batch)
 			batch.Close()
 			if err != nil {
 				panic(err)
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/operation"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+type varsResult struct {
+	File      string                 `json:"file"`
+	Variables map[string]interface{} `json:"variables"`
+	Output    string                 `json:"output,omitempty"`
+	Skipped   string                 `json:"skipped,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+var varsCmd = &cobra.Command{
+	Use:   "vars {file(s)}",
+	Short: "Show template variables for files",
+	Long:  `Print every variable a template can use for the files, use --template to test rendering a template`,
+	Args:  cobra.MinimumNArgs(1),
+
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options.GetVarsOptions(cmd)
+		template, err := snippets.FromString(opts.Template)
+		if err != nil {
+			pterm.Error.WithShowLineNumber(false).Println(err.Error())
+			os.Exit(1)
+		}
+		if _, err := util.LoadTimeZone(opts.TimeZone); err != nil {
+			fmt.Println("Invalid Time Zone: ", err.Error())
+			os.Exit(1)
+		}
+		operations := operation.FilesToOperationsList("vars", args, options.CommonOptions{TimeZone: opts.TimeZone}, template)
+		results := []varsResult{}
+		failed := false
+		for _, op := range operations {
+			result := varsResult{File: op.Input.Rel, Variables: op.Variables()}
+			if opts.Template != "" {
+				result.Output, result.Skipped, err = op.TestTemplate()
+				if err != nil {
+					result.Error = err.Error()
+					failed = true
+				}
+			}
+			results = append(results, result)
+		}
+		db.CloseDB() // the hash function may have opened the cache
+		if opts.JSON {
+			out, _ := json.MarshalIndent(results, "", "  ")
+			fmt.Println(string(out))
+		} else {
+			for _, result := range results {
+				pterm.DefaultSection.Println(result.File)
+				pterm.DefaultTree.WithRoot(pterm.NewTreeFromLeveledList(operation.VariablesTree(result.Variables))).Render()
+				switch {
+				case opts.Template == "":
+				case result.Error != "":
+					pterm.Error.WithShowLineNumber(false).Println(result.Error)
+				case result.Skipped != "":
+					pterm.Info.Printfln("Skipped because %s", result.Skipped)
+				default:
+					pterm.Success.Println(result.Output)
+				}
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(varsCmd)
+	varsCmd.Flags().StringP("template", "t", options.Template, "Template to render for each file")
+	varsCmd.Flags().Bool("json", options.JSON, "Output results as JSON")
+	varsCmd.Flags().String("tz", options.TimeZone, "Time zone to render dates in, such as America/New_York or UTC")
+}
@@ -3,17 +3,23 @@ module github.com/jhotmann/go-fileutils-cli
 go 1.16
 
 require (
-	github.com/1set/gut v0.0.0-20201117175203-a82363231997 // indirect
-	github.com/atotto/clipboard v0.1.4 // indirect
-	github.com/dlclark/regexp2 v1.4.0 // indirect
+	github.com/1set/gut v0.0.0-20201117175203-a82363231997
+	github.com/atotto/clipboard v0.1.4
+	github.com/cespare/xxhash/v2 v2.1.1
+	github.com/dlclark/regexp2 v1.4.0
 	github.com/eiannone/keyboard v0.0.0-20200508000154-caf4b762e807 // indirect
-	github.com/flosch/pongo2/v4 v4.0.2 // indirect
-	github.com/iancoleman/strcase v0.1.3 // indirect
-	github.com/manifoldco/promptui v0.8.0 // indirect
+	github.com/flosch/pongo2/v4 v4.0.2
+	github.com/iancoleman/strcase v0.1.3
+	github.com/manifoldco/promptui v0.8.0
 	github.com/mitchellh/go-homedir v1.1.0
-	github.com/pterm/pterm v0.12.17 // indirect
+	github.com/mozillazg/go-unidecode v0.2.0
+	github.com/pterm/pterm v0.12.17
 	github.com/spf13/cobra v1.1.3
 	github.com/spf13/viper v1.7.1
-	go.etcd.io/bbolt v1.3.5 // indirect
-	golang.org/x/sys v0.0.0-20210514084401-e8d321eab015 // indirect
+	github.com/zeebo/xxh3 v1.0.1
+	go.etcd.io/bbolt v1.3.5
+	golang.org/x/crypto v0.0.0-20210513164829-c07d793c2f9a
+	golang.org/x/sys v0.0.0-20210514084401-e8d321eab015
+	golang.org/x/text v0.3.3
+	lukechampine.com/blake3 v1.1.7
 )
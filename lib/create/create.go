@@ -0,0 +1,200 @@
+package create
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flosch/pongo2/v4"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/filters"
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+	"github.com/jhotmann/go-fileutils-cli/lib/tags"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+// Names combines the names from arguments, a file with one name per line and a range like 1..12
+func Names(args []string, namesFile string, numberRange string) ([]string, error) {
+	names := append([]string{}, args...)
+	if namesFile != "" {
+		f, err := os.Open(namesFile)
+		if err != nil {
+			return names, err
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if name := strings.TrimSpace(scanner.Text()); name != "" {
+				names = append(names, name)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return names, err
+		}
+	}
+	if numberRange != "" {
+		numbers, err := ParseRange(numberRange)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, numbers...)
+	}
+	return names, nil
+}
+
+// ParseRange expands start..end into every number between them, counting down when start is bigger
+func ParseRange(numberRange string) ([]string, error) {
+	parts := strings.Split(numberRange, "..")
+	if len(parts) != 2 {
+		return nil, errors.New("range must look like start..end, such as 1..12")
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, errors.New("range start " + parts[0] + " is not an integer")
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, errors.New("range end " + parts[1] + " is not an integer")
+	}
+	step := 1
+	if end < start {
+		step = -1
+	}
+	numbers := []string{}
+	for n := start; n != end+step; n += step {
+		numbers = append(numbers, strconv.Itoa(n))
+	}
+	return numbers, nil
+}
+
+// Vars reads the vars from the config file and overrides them with key=value pairs
+func Vars(pairs []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for key, value := range viper.GetStringMapString("vars") {
+		vars[key] = value
+	}
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return vars, errors.New(pair + " must look like key=value")
+		}
+		vars[parts[0]] = parts[1]
+	}
+	return vars, nil
+}
+
+// Render renders the template once per name (or once without names) and returns the unique paths
+func Render(template *pongo2.Template, names []string, vars map[string]string, opts options.CreateOptions) ([]string, error) {
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	paths := []string{}
+	seen := map[string]bool{}
+	loc, err := util.LoadTimeZone(opts.TimeZone)
+	if err != nil {
+		return paths, err
+	}
+	filters.SetParseLocation(loc)
+	for i, name := range names {
+		out, err := template.Execute(pongo2.Context{
+			"name":  name,
+			"n":     i + 1,
+			"total": len(names),
+			"vars":  vars,
+			"date":  map[string]time.Time{"now": time.Now().In(loc)},
+			"use":   snippets.Use,
+		})
+		if err != nil {
+			return paths, err
+		}
+		if strings.Contains(out, tags.SkipMarker) {
+			if opts.Verbose {
+				pterm.Info.Printfln("Skipping %s because the template called skip", name)
+			}
+			continue
+		}
+		if strings.TrimSpace(out) == "" {
+			if opts.Verbose {
+				pterm.Info.Printfln("Skipping %s because the output is empty", name)
+			}
+			continue
+		}
+		path := filepath.Clean(out)
+		if opts.Portable != "" {
+			cleaned, err := sanitizeRelative(path, opts)
+			if err != nil {
+				return paths, err
+			}
+			path = cleaned
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// sanitizeRelative cleans a path with SanitizePath, which needs an absolute path, and keeps relative paths relative
+func sanitizeRelative(path string, opts options.CreateOptions) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path, err
+	}
+	cleaned, changes, err := util.SanitizePath(abs, opts.Portable)
+	if err != nil {
+		return path, err
+	}
+	if !filepath.IsAbs(path) {
+		if cleaned, err = filepath.Rel(util.GetWorkingDir(), cleaned); err != nil {
+			return path, err
+		}
+	}
+	if opts.Verbose && len(changes) > 0 {
+		pterm.Info.Printfln("%s → %s (%s)", path, cleaned, strings.Join(changes, ", "))
+	}
+	return cleaned, nil
+}
+
+// Directory creates a directory and any missing parents, returning the directories that were created from the top down
+func Directory(path string) ([]string, error) {
+	missing := []string{}
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(dir); err == nil || dir == filepath.Dir(dir) {
+			break
+		}
+		missing = append([]string{dir}, missing...)
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// File creates an empty file and its missing directories, the times of existing files are updated like touch.
+// The paths that were created are returned from the top down.
+func File(path string) ([]string, error) {
+	created, err := Directory(filepath.Dir(path))
+	if err != nil {
+		return created, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		now := time.Now()
+		return created, os.Chtimes(path, now, now)
+	}
+	if err != nil {
+		return created, err
+	}
+	if err := f.Close(); err != nil {
+		return created, err
+	}
+	return append(created, path), nil
+}
@@ -52,7 +52,7 @@ func NewBatch(commandType string, command []string, workingDir string) Batch {
 		batch.Command = command
 		batch.CommandString = strings.Join(command, " ")
 		batch.WorkingDir = workingDir
-		batch.Undoable = util.IndexOf(commandType, []string{"move", "copy", "link-soft", "link-hard"}) > -1
+		batch.Undoable = util.IndexOf(commandType, []string{"move", "copy", "link-soft", "link-hard", "dedupe", "trash", "mkdir", "touch"}) > -1
 		batch.Undone = false
 		batch.Date = time.Now()
 		buff, err := json.Marshal(batch)
@@ -104,25 +104,18 @@ func GetLastNonUndone() (Batch, error) {
 	return batch, err
 }
 
-func (batch Batch) Undo() error {
-	if batch.Undone {
-		pterm.Warning.Println("Batch already undone")
-		return errors.New("Batch already undone")
-	}
-	err := db.Update(func(tx *bolt.Tx) error {
+// MarkUndone saves the batch as undone
+func (batch Batch) MarkUndone() error {
+	batch.Undone = true
+	OpenDB()
+	return db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte("batches"))
-		batch.Undone = true
-		buff, _ := json.Marshal(batch)
+		buff, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
 		return b.Put(itob(batch.Id), buff)
 	})
-	if err != nil {
-		return err
-	}
-	operations, err := GetOperationsForBatch(batch.Id)
-	if err != nil {
-		return err
-	}
-	return operations.Undo(batch.CommandType, batch.WorkingDir)
 }
 
 func (b BatchList) ToTableData() pterm.TableData {
@@ -3,6 +3,7 @@ package db
 import (
 	"encoding/binary"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/mitchellh/go-homedir"
@@ -14,6 +15,7 @@ var (
 	home   string
 	err    error
 	dbPath string
+	dbLock sync.Mutex
 )
 
 func init() {
@@ -26,6 +28,8 @@ func init() {
 }
 
 func OpenDB() *bolt.DB {
+	dbLock.Lock()
+	defer dbLock.Unlock()
 	if db == nil {
 		db, err = bolt.Open(dbPath, 0755, &bolt.Options{Timeout: 2 * time.Second})
 		if err != nil {
@@ -36,6 +40,39 @@ func OpenDB() *bolt.DB {
 	return db
 }
 
+// TryOpenDB opens the database like OpenDB but returns an error instead of panicking when it can't be opened or
+// another process holds the lock for longer than timeout
+func TryOpenDB(timeout time.Duration) (*bolt.DB, error) {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+	if db == nil {
+		opened, err := bolt.Open(dbPath, 0755, &bolt.Options{Timeout: timeout})
+		if err != nil {
+			return nil, err
+		}
+		db = opened
+	}
+	return db, nil
+}
+
+// SetPath closes the open database and uses the one at path from then on
+func SetPath(path string) {
+	CloseDB()
+	dbLock.Lock()
+	defer dbLock.Unlock()
+	dbPath = path
+}
+
+func CloseDB() {
+	dbLock.Lock()
+	defer dbLock.Unlock()
+	if db == nil {
+		return
+	}
+	db.Close()
+	db = nil
+}
+
 func itob(v int) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, uint64(v))
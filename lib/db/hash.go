@@ -0,0 +1,88 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type CachedHash struct {
+	Path      string    `json:"Path"`
+	Algorithm string    `json:"Algorithm"`
+	Digest    string    `json:"Digest"`
+	Date      time.Time `json:"Date"`
+}
+
+// cacheTimeout is how long the hash cache waits for another fu process to release the database
+const cacheTimeout = 100 * time.Millisecond
+
+// GetCachedHash looks up a digest by a key identifying the file's device, inode, size, mtime and algorithm,
+// an error means the database couldn't be opened
+func GetCachedHash(key string) (CachedHash, bool, error) {
+	var cached CachedHash
+	found := false
+	if _, err := TryOpenDB(cacheTimeout); err != nil {
+		return cached, false, err
+	}
+	db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte("hashes"))
+		if b == nil {
+			return nil
+		}
+		v := b.Get([]byte(key))
+		if v == nil {
+			return nil
+		}
+		found = json.Unmarshal(v, &cached) == nil
+		return nil
+	})
+	return cached, found, nil
+}
+
+func WriteCachedHash(key string, path string, algorithm string, digest string) error {
+	if _, err := TryOpenDB(cacheTimeout); err != nil {
+		return err
+	}
+	return db.Batch(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("hashes"))
+		if err != nil {
+			return err
+		}
+		buff, err := json.Marshal(CachedHash{Path: path, Algorithm: algorithm, Digest: digest, Date: time.Now()})
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(key), buff)
+	})
+}
+
+// PruneHashCache deletes cached digests that isValid rejects, such as ones for files that changed or no longer exist
+func PruneHashCache(isValid func(key string, cached CachedHash) bool) (int, error) {
+	pruned := 0
+	if _, err := TryOpenDB(2 * time.Second); err != nil {
+		return 0, err
+	}
+	err := db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("hashes"))
+		if err != nil {
+			return err
+		}
+		stale := [][]byte{}
+		b.ForEach(func(k, v []byte) error {
+			var cached CachedHash
+			if json.Unmarshal(v, &cached) != nil || !isValid(string(k), cached) {
+				stale = append(stale, append([]byte{}, k...))
+			}
+			return nil
+		})
+		for _, k := range stale {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+			pruned++
+		}
+		return nil
+	})
+	return pruned, err
+}
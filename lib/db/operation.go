@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pterm/pterm"
 	bolt "go.etcd.io/bbolt"
@@ -16,6 +17,13 @@ type Operation struct {
 	Input   string `json:"Input"`
 	Output  string `json:"Output"`
 	Undone  bool   `json:"Undone"`
+	// Hash is the output's hash after a single file was copied with --verify, or the kept file's hash for dedupe.
+	// Algorithm is the hash algorithm, it is also set for directories copied with --verify.
+	Hash      string `json:"Hash,omitempty"`
+	Algorithm string `json:"Algorithm,omitempty"`
+	// a deduplicated output's original permissions and modified time, so undo can restore it from the input
+	Mode     os.FileMode `json:"Mode,omitempty"`
+	Modified *time.Time  `json:"Modified,omitempty"`
 }
 
 type OperationList []Operation
@@ -43,20 +51,38 @@ func GetOperationsForBatch(batchId int) (OperationList, error) {
 	return operations, err
 }
 
-func WriteOperation(batchId int, input string, output string) error {
+func WriteOperation(batchId int, input string, output string, algorithm string, hash string) error {
+	return writeOperation(Operation{
+		BatchId:   batchId,
+		Input:     input,
+		Output:    output,
+		Hash:      hash,
+		Algorithm: algorithm,
+	})
+}
+
+// WriteDedupeOperation records a duplicate (output) that was removed or replaced by a link to the file that was kept (input)
+func WriteDedupeOperation(batchId int, kept string, duplicate string, stats os.FileInfo, algorithm string, hash string) error {
+	modified := stats.ModTime()
+	return writeOperation(Operation{
+		BatchId:   batchId,
+		Input:     kept,
+		Output:    duplicate,
+		Hash:      hash,
+		Algorithm: algorithm,
+		Mode:      stats.Mode().Perm(),
+		Modified:  &modified,
+	})
+}
+
+func writeOperation(op Operation) error {
 	return db.Batch(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte("operations"))
 		id, err := b.NextSequence()
 		if err != nil {
 			return err
 		}
-		op := Operation{
-			Id:      int(id),
-			BatchId: batchId,
-			Input:   input,
-			Output:  output,
-			Undone:  false,
-		}
+		op.Id = int(id)
 		buff, err := json.Marshal(op)
 		if err != nil {
 			return err
@@ -74,39 +100,24 @@ func (ops OperationList) ToTableData(cwd string) pterm.TableData {
 	return ret
 }
 
-func (ops OperationList) Undo(commandType string, cwd string) error {
-	var err error
-	return db.Batch(func(tx *bolt.Tx) error {
+func (ops OperationList) Reverse() OperationList {
+	ret := OperationList{}
+	for _, op := range ops {
+		ret = append(OperationList{op}, ret...)
+	}
+	return ret
+}
+
+// MarkUndone saves the operation as undone
+func (op Operation) MarkUndone() error {
+	op.Undone = true
+	OpenDB()
+	return db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte("operations"))
-		for _, op := range ops {
-			input := strings.Replace(op.Input, cwd, "", 1)
-			output := strings.Replace(op.Output, cwd, "", 1)
-			if op.Undone {
-				pterm.Info.Printfln("%s already undone", output)
-				continue
-			}
-			if commandType == "move" {
-				err = os.Rename(op.Output, op.Input)
-				if err != nil {
-					pterm.Warning.Printfln("Could not move %s back to %s", output, input)
-					return err
-				}
-				pterm.Success.Printfln("%s → %s", output, input)
-			} else {
-				err = os.RemoveAll(op.Output)
-				if err != nil {
-					pterm.Warning.Printfln("Could not delete %s", op.Output)
-					return err
-				}
-				pterm.Success.Printfln("Deleted %s", output)
-			}
-			op.Undone = true
-			buff, _ := json.Marshal(op)
-			err = b.Put(itob(op.Id), buff)
-			if err != nil {
-				return err
-			}
+		buff, err := json.Marshal(op)
+		if err != nil {
+			return err
 		}
-		return nil
+		return b.Put(itob(op.Id), buff)
 	})
 }
@@ -0,0 +1,263 @@
+package dedupe
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pterm/pterm"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+// partialSize is how much of each file is hashed to rule out files of the same size before reading them completely
+const partialSize = 64 * 1024
+
+type File struct {
+	Path  string
+	Stats os.FileInfo
+}
+
+type Group struct {
+	Size   int64
+	Digest string
+	Files  []File
+}
+
+type GroupList []Group
+
+// Find groups files with identical contents. Files are compared by size, then by a hash of their first 64 KiB and
+// finally by a hash of their whole contents. Empty files and files that are already hard links to each other are ignored.
+func Find(paths []string, useCache bool) (GroupList, []error) {
+	problems := []error{}
+	bySize := map[int64][]File{}
+	for _, path := range paths {
+		stats, err := os.Lstat(path)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if !stats.Mode().IsRegular() || stats.Size() == 0 || isLinked(stats, bySize[stats.Size()]) {
+			continue
+		}
+		bySize[stats.Size()] = append(bySize[stats.Size()], File{Path: path, Stats: stats})
+	}
+
+	algo := hash.Algorithms[hash.CompareAlgorithm]
+	candidates := [][]File{}
+	for size, files := range bySize {
+		if len(files) < 2 {
+			continue
+		}
+		if size <= partialSize { // the partial hash would be the full hash
+			candidates = append(candidates, files)
+			continue
+		}
+		groups, errs := groupByDigest(files, hash.PartialFiles(filePaths(files), algo, partialSize))
+		problems = append(problems, errs...)
+		for _, group := range groups {
+			candidates = append(candidates, group)
+		}
+	}
+
+	duplicates := GroupList{}
+	for _, files := range candidates {
+		groups, errs := groupByDigest(files, hash.Files(filePaths(files), algo, useCache))
+		problems = append(problems, errs...)
+		for digest, group := range groups {
+			duplicates = append(duplicates, Group{Size: group[0].Stats.Size(), Digest: digest, Files: group})
+		}
+	}
+	for _, group := range duplicates {
+		sort.Slice(group.Files, func(i, j int) bool { return group.Files[i].Path < group.Files[j].Path })
+	}
+	// the groups that waste the most space first
+	sort.Slice(duplicates, func(i, j int) bool {
+		wastedI, wastedJ := duplicates[i].Wasted(), duplicates[j].Wasted()
+		if wastedI != wastedJ {
+			return wastedI > wastedJ
+		}
+		return duplicates[i].Files[0].Path < duplicates[j].Files[0].Path
+	})
+	return duplicates, problems
+}
+
+// groupByDigest splits files by their hashes, groups with a single file are dropped
+func groupByDigest(files []File, results hash.ResultList) (map[string][]File, []error) {
+	problems := []error{}
+	groups := map[string][]File{}
+	for i, result := range results {
+		if result.Error != "" {
+			problems = append(problems, errors.New(result.Error))
+			continue
+		}
+		groups[result.Digest] = append(groups[result.Digest], files[i])
+	}
+	for digest, group := range groups {
+		if len(group) < 2 {
+			delete(groups, digest)
+		}
+	}
+	return groups, problems
+}
+
+func isLinked(stats os.FileInfo, files []File) bool {
+	for _, f := range files {
+		if os.SameFile(stats, f.Stats) {
+			return true
+		}
+	}
+	return false
+}
+
+func filePaths(files []File) []string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths
+}
+
+// Wasted is the space used by every copy except one
+func (g Group) Wasted() int64 {
+	return g.Size * int64(len(g.Files)-1)
+}
+
+// Keep picks the file to keep and returns the others as duplicates. Files matching the keep glob (by path or name)
+// are preferred, the strategy (oldest, newest or shortest path) picks between the remaining files.
+func (g Group) Keep(strategy string, keep string) (File, []File) {
+	candidates := []File{}
+	if keep != "" {
+		for _, f := range g.Files {
+			pathMatch, _ := filepath.Match(keep, f.Path)
+			nameMatch, _ := filepath.Match(keep, filepath.Base(f.Path))
+			if pathMatch || nameMatch {
+				candidates = append(candidates, f)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = append(candidates, g.Files...)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch strategy {
+		case "newest":
+			return a.Stats.ModTime().After(b.Stats.ModTime())
+		case "shortest":
+			return len(a.Path) < len(b.Path)
+		default:
+			return a.Stats.ModTime().Before(b.Stats.ModTime())
+		}
+	})
+	kept := candidates[0]
+	duplicates := []File{}
+	for _, f := range g.Files {
+		if f.Path != kept.Path {
+			duplicates = append(duplicates, f)
+		}
+	}
+	return kept, duplicates
+}
+
+// Resolve deletes a duplicate or replaces it with a hard or symbolic link to the kept file. The files are compared
+// byte for byte first since their hashes may have come from the cache or collided.
+func Resolve(kept File, duplicate File, action string) error {
+	same, err := sameContents(kept.Path, duplicate.Path)
+	if err != nil {
+		return err
+	}
+	if !same {
+		return errors.New(duplicate.Path + " is no longer identical to " + kept.Path + ", it was left alone")
+	}
+	if action == "delete" {
+		return os.Remove(duplicate.Path)
+	}
+	// create the link next to the duplicate then rename it over the duplicate so it is never missing
+	temp := duplicate.Path + ".fu-dedupe"
+	switch action {
+	case "hardlink":
+		err = os.Link(kept.Path, temp)
+	case "symlink":
+		var target string
+		target, err = filepath.Abs(kept.Path)
+		if err == nil {
+			err = os.Symlink(target, temp)
+		}
+	default:
+		return errors.New(action + " not implemented")
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(temp, duplicate.Path); err != nil {
+		os.Remove(temp)
+		return err
+	}
+	return nil
+}
+
+// sameContents reads both files completely and compares them byte for byte
+func sameContents(a string, b string) (bool, error) {
+	fileA, err := os.Open(a)
+	if err != nil {
+		return false, err
+	}
+	defer fileA.Close()
+	fileB, err := os.Open(b)
+	if err != nil {
+		return false, err
+	}
+	defer fileB.Close()
+	bufA, bufB := make([]byte, 64*1024), make([]byte, 64*1024)
+	for {
+		nA, errA := io.ReadFull(fileA, bufA)
+		nB, errB := io.ReadFull(fileB, bufB)
+		if !bytes.Equal(bufA[:nA], bufB[:nB]) {
+			return false, nil
+		}
+		endA := errA == io.EOF || errA == io.ErrUnexpectedEOF
+		endB := errB == io.EOF || errB == io.ErrUnexpectedEOF
+		if errA != nil && !endA {
+			return false, errA
+		}
+		if errB != nil && !endB {
+			return false, errB
+		}
+		if endA || endB {
+			return endA && endB, nil
+		}
+	}
+}
+
+func (g GroupList) Wasted() int64 {
+	var wasted int64
+	for _, group := range g {
+		wasted += group.Wasted()
+	}
+	return wasted
+}
+
+func (g GroupList) ToTableData(strategy string, keep string, action string) pterm.TableData {
+	if action == "" {
+		action = "duplicate"
+	}
+	ret := [][]string{}
+	ret = append(ret, []string{"Group", "Size", "File", "Modified", "Action"})
+	for i, group := range g {
+		kept, _ := group.Keep(strategy, keep)
+		for _, f := range group.Files {
+			fileAction := action
+			if f.Path == kept.Path {
+				fileAction = "keep"
+			}
+			ret = append(ret, []string{fmt.Sprintf("%d", i+1), util.FormatBytes(group.Size, false), f.Path, f.Stats.ModTime().Format("Jan 2, 2006 15:04:05"), fileAction})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,132 @@
+package filters
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/flosch/pongo2/v4"
+	"github.com/spf13/viper"
+)
+
+var (
+	titleStopWords = []string{"a", "an", "the", "and", "but", "or", "nor", "for", "so", "yet", "as", "at", "by",
+		"in", "of", "off", "on", "per", "to", "up", "via", "vs", "from", "into", "onto", "with"}
+	// DefaultAcronyms are kept upper case by title and sentence, more can be added with the acronyms config key
+	DefaultAcronyms = []string{"AI", "BBC", "CD", "CEO", "DC", "DIY", "DJ", "DVD", "EU", "FAQ", "FBI", "HD", "HTML",
+		"ID", "NASA", "NYC", "OK", "PC", "PDF", "TV", "UK", "USA", "VIP"}
+)
+
+type caseWord struct {
+	text      string
+	separator bool
+}
+
+func titleFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	acronyms := getAcronyms(param)
+	var sb strings.Builder
+	first := true
+	for _, w := range splitWords(in.String()) {
+		switch {
+		case w.separator:
+			sb.WriteString(w.text)
+			continue
+		case isAcronym(w.text, acronyms):
+			sb.WriteString(strings.ToUpper(w.text))
+		case !first && isStopWord(w.text):
+			sb.WriteString(strings.ToLower(w.text))
+		default:
+			sb.WriteString(capitalize(w.text))
+		}
+		first = false
+	}
+	return pongo2.AsValue(sb.String()), nil
+}
+
+func sentenceFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	acronyms := getAcronyms(param)
+	var sb strings.Builder
+	start := true
+	for _, w := range splitWords(in.String()) {
+		switch {
+		case w.separator:
+			sb.WriteString(w.text)
+			if strings.ContainsAny(w.text, ".!?") {
+				start = true
+			}
+			continue
+		case isAcronym(w.text, acronyms):
+			sb.WriteString(strings.ToUpper(w.text))
+		case start:
+			sb.WriteString(capitalize(w.text))
+		default:
+			sb.WriteString(strings.ToLower(w.text))
+		}
+		start = false
+	}
+	return pongo2.AsValue(sb.String()), nil
+}
+
+// splitWords breaks a string into words and the separators between them so the separators can be kept
+func splitWords(s string) []caseWord {
+	words := []caseWord{}
+	var current strings.Builder
+	currentIsWord := false
+	for _, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '’'
+		if current.Len() > 0 && isWord != currentIsWord {
+			words = append(words, caseWord{text: current.String(), separator: !currentIsWord})
+			current.Reset()
+		}
+		currentIsWord = isWord
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		words = append(words, caseWord{text: current.String(), separator: !currentIsWord})
+	}
+	return words
+}
+
+func capitalize(s string) string {
+	runes := []rune(strings.ToLower(s))
+	if len(runes) == 0 {
+		return s
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func isStopWord(s string) bool {
+	for _, w := range titleStopWords {
+		if strings.EqualFold(w, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAcronym(s string, acronyms []string) bool {
+	for _, a := range acronyms {
+		if strings.EqualFold(a, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// getAcronyms combines the default acronyms, the acronyms config key and any passed to the filter separated by commas
+func getAcronyms(param *pongo2.Value) []string {
+	acronyms := append([]string{}, DefaultAcronyms...)
+	acronyms = append(acronyms, viper.GetStringSlice("acronyms")...)
+	if !param.IsNil() {
+		for _, a := range strings.Split(param.String(), ",") {
+			acronyms = append(acronyms, strings.TrimSpace(a))
+		}
+	}
+	return acronyms
+}
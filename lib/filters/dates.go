@@ -0,0 +1,513 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+// dateToken is either a run of a single pattern letter or literal text
+type dateToken struct {
+	letter  rune
+	count   int
+	literal string
+}
+
+// layouts tried in order when parseDate is not given a pattern
+var (
+	isoDateLayouts = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02_15-04-05",
+		"2006-01-02_15.04.05",
+		"2006-01-02 15.04.05",
+		"20060102_150405",
+		"20060102-150405",
+		"20060102T150405",
+		"2006-01-02",
+		"2006_01_02",
+		"2006.01.02",
+		"20060102",
+	}
+	euDateLayouts = []string{"2.1.2006 15:04:05", "2.1.2006", "2/1/2006", "2-1-2006", "2.1.06", "2/1/06"}
+	usDateLayouts = []string{"1/2/2006 15:04:05", "1/2/2006", "1-2-2006", "1/2/06", "1.2.2006"}
+	quarterNames  = []string{"1st quarter", "2nd quarter", "3rd quarter", "4th quarter"}
+)
+
+func DateFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	t, isTime := in.Interface().(time.Time)
+	if !isTime {
+		return nil, &pongo2.Error{
+			Sender:    "filter:date",
+			OrigError: errors.New("filter input argument must be of type 'time.Time'"),
+		}
+	}
+	out, err := formatUnicodeDate(t, param.String())
+	if err != nil {
+		return nil, &pongo2.Error{Sender: "filter:date", OrigError: err}
+	}
+	return pongo2.AsValue(out), nil
+}
+
+// parseLocation is the time zone parseDate reads dates without an offset in, commands set it to the --tz
+// time zone so parsed dates line up with the date variables
+var parseLocation = time.Local
+
+// SetParseLocation sets the time zone parseDate uses for dates without an offset
+func SetParseLocation(loc *time.Location) {
+	parseLocation = loc
+}
+
+func parseDateFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if in.IsTime() {
+		return in, nil
+	}
+	s := strings.TrimSpace(in.String())
+	var layouts []string
+	switch p := param.String(); p {
+	case "", "auto", "eu":
+		layouts = append(isoDateLayouts, euDateLayouts...)
+	case "us":
+		layouts = append(isoDateLayouts, usDateLayouts...)
+	default:
+		layout, err := unicodeToGoDateFormat(p)
+		if err != nil {
+			return nil, &pongo2.Error{Sender: "filter:parseDate", OrigError: err}
+		}
+		layouts = []string{layout}
+	}
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, s, parseLocation)
+		if err == nil {
+			return pongo2.AsValue(t), nil
+		}
+	}
+	return nil, &pongo2.Error{
+		Sender:    "filter:parseDate",
+		OrigError: errors.New("could not parse a date from '" + s + "'"),
+	}
+}
+
+// tzFilter converts a time to the named IANA time zone, such as "America/New_York", "UTC" or "Local"
+func tzFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	t, isTime := in.Interface().(time.Time)
+	if !isTime {
+		return nil, &pongo2.Error{
+			Sender:    "filter:tz",
+			OrigError: errors.New("filter input argument must be of type 'time.Time'"),
+		}
+	}
+	loc, err := time.LoadLocation(param.String())
+	if err != nil {
+		return nil, &pongo2.Error{Sender: "filter:tz", OrigError: err}
+	}
+	return pongo2.AsValue(t.In(loc)), nil
+}
+
+// tokenizeDatePattern splits a Unicode (CLDR) date pattern into runs of pattern letters and
+// literal text, text between single quotes is literal and two single quotes are a single quote
+func tokenizeDatePattern(pattern string) ([]dateToken, error) {
+	tokens := []dateToken{}
+	runes := []rune(pattern)
+	addLiteral := func(s string) {
+		if len(tokens) > 0 && tokens[len(tokens)-1].letter == 0 {
+			tokens[len(tokens)-1].literal += s
+		} else {
+			tokens = append(tokens, dateToken{literal: s})
+		}
+	}
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'':
+			if i+1 < len(runes) && runes[i+1] == '\'' {
+				addLiteral("'")
+				i++
+				continue
+			}
+			var sb strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						sb.WriteRune('\'')
+						i++
+						continue
+					}
+					closed = true
+					break
+				}
+				sb.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, errors.New("unterminated quote in date pattern '" + pattern + "'")
+			}
+			addLiteral(sb.String())
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			count := 1
+			for i+1 < len(runes) && runes[i+1] == r {
+				count++
+				i++
+			}
+			tokens = append(tokens, dateToken{letter: r, count: count})
+		default:
+			addLiteral(string(r))
+		}
+	}
+	return tokens, nil
+}
+
+// formatUnicodeDate formats a time with a Unicode (CLDR) date pattern such as "yyyy-MM-dd 'at' HH:mm"
+func formatUnicodeDate(t time.Time, pattern string) (string, error) {
+	tokens, err := tokenizeDatePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, token := range tokens {
+		if token.letter == 0 {
+			sb.WriteString(token.literal)
+		} else {
+			sb.WriteString(formatDateToken(t, token))
+		}
+	}
+	return sb.String(), nil
+}
+
+func formatDateToken(t time.Time, token dateToken) string {
+	n := token.count
+	isoYear, isoWeek := t.ISOWeek()
+	switch token.letter {
+	case 'G': // era
+		era := "AD"
+		if t.Year() <= 0 {
+			era = "BC"
+		}
+		switch {
+		case n == 4 && era == "AD":
+			return "Anno Domini"
+		case n == 4:
+			return "Before Christ"
+		case n == 5:
+			return era[:1]
+		}
+		return era
+	case 'y', 'u': // year
+		return formatYear(t.Year(), n)
+	case 'Y': // year of the ISO week
+		return formatYear(isoYear, n)
+	case 'Q', 'q': // quarter
+		quarter := (int(t.Month())-1)/3 + 1
+		switch n {
+		case 3:
+			return fmt.Sprintf("Q%d", quarter)
+		case 4:
+			return quarterNames[quarter-1]
+		case 5:
+			return fmt.Sprintf("%d", quarter)
+		}
+		return pad(quarter, n)
+	case 'M', 'L': // month
+		switch n {
+		case 3:
+			return t.Format("Jan")
+		case 4:
+			return t.Format("January")
+		case 5:
+			return t.Format("Jan")[:1]
+		}
+		return pad(int(t.Month()), n)
+	case 'w': // ISO week of year
+		return pad(isoWeek, n)
+	case 'W': // week of month, weeks start on Monday
+		first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		offset := (int(first.Weekday()) + 6) % 7
+		return pad((t.Day()+offset-1)/7+1, n)
+	case 'd': // day of month
+		return pad(t.Day(), n)
+	case 'D': // day of year
+		return pad(t.YearDay(), n)
+	case 'F': // day of week in month
+		return pad((t.Day()-1)/7+1, n)
+	case 'E': // day of week name
+		return formatWeekday(t, n)
+	case 'e', 'c': // local day of week, numbers start on Monday
+		if n <= 2 {
+			return pad((int(t.Weekday())+6)%7+1, n)
+		}
+		return formatWeekday(t, n)
+	case 'a', 'b', 'B': // period of the day
+		return formatDayPeriod(t, token.letter, n)
+	case 'h': // hour 1-12
+		return pad((t.Hour()+11)%12+1, n)
+	case 'H': // hour 0-23
+		return pad(t.Hour(), n)
+	case 'K': // hour 0-11
+		return pad(t.Hour()%12, n)
+	case 'k': // hour 1-24
+		return pad((t.Hour()+23)%24+1, n)
+	case 'm':
+		return pad(t.Minute(), n)
+	case 's':
+		return pad(t.Second(), n)
+	case 'S': // fractional seconds
+		fraction := fmt.Sprintf("%09d", t.Nanosecond())
+		if n <= 9 {
+			return fraction[:n]
+		}
+		return fraction + strings.Repeat("0", n-9)
+	case 'A': // milliseconds in day
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return pad(int(t.Sub(midnight)/time.Millisecond), n)
+	case 'z': // specific non-location zone
+		if n == 4 {
+			return formatGMTOffset(t, true)
+		}
+		return t.Format("MST")
+	case 'Z':
+		switch n {
+		case 4:
+			return formatGMTOffset(t, true)
+		case 5:
+			return formatISOOffset(t, true, true, true)
+		}
+		return formatISOOffset(t, false, true, false)
+	case 'O': // localized GMT offset
+		return formatGMTOffset(t, n == 4)
+	case 'v': // generic non-location zone
+		if n == 4 {
+			return zoneCity(t) + " Time"
+		}
+		return t.Format("MST")
+	case 'V': // zone id
+		switch n {
+		case 1:
+			return "unk"
+		case 3:
+			return zoneCity(t)
+		case 4:
+			return zoneCity(t) + " Time"
+		}
+		return zoneName(t)
+	case 'X', 'x': // ISO 8601 offset, X uses Z for UTC
+		_, offset := t.Zone()
+		if token.letter == 'X' && offset == 0 {
+			return "Z"
+		}
+		switch n {
+		case 1:
+			out := formatISOOffset(t, false, false, false)
+			if offset%3600 == 0 {
+				return out[:3]
+			}
+			return out
+		case 2, 4:
+			return formatISOOffset(t, false, false, false)
+		}
+		return formatISOOffset(t, true, false, false)
+	}
+	// unknown letters are output as is
+	return strings.Repeat(string(token.letter), n)
+}
+
+func pad(i int, width int) string {
+	return fmt.Sprintf("%0*d", width, i)
+}
+
+func formatYear(year int, n int) string {
+	if n == 2 {
+		return pad(year%100, 2)
+	}
+	return pad(year, n)
+}
+
+func formatWeekday(t time.Time, n int) string {
+	switch n {
+	case 4:
+		return t.Format("Monday")
+	case 5:
+		return t.Format("Mon")[:1]
+	case 6:
+		return t.Format("Mon")[:2]
+	}
+	return t.Format("Mon")
+}
+
+func formatDayPeriod(t time.Time, letter rune, n int) string {
+	hour, minute := t.Hour(), t.Minute()
+	if letter == 'B' {
+		switch {
+		case hour >= 6 && hour < 12:
+			return "in the morning"
+		case hour >= 12 && hour < 18:
+			return "in the afternoon"
+		case hour >= 18 && hour < 21:
+			return "in the evening"
+		}
+		return "at night"
+	}
+	if letter == 'b' && minute == 0 && t.Second() == 0 && (hour == 0 || hour == 12) {
+		if hour == 0 {
+			return "midnight"
+		}
+		return "noon"
+	}
+	period := "AM"
+	if hour >= 12 {
+		period = "PM"
+	}
+	if n == 5 {
+		return strings.ToLower(period[:1])
+	}
+	return period
+}
+
+// formatISOOffset renders a zone offset like +0100, +01:00 or Z
+func formatISOOffset(t time.Time, colon bool, seconds bool, utcZ bool) string {
+	_, offset := t.Zone()
+	if utcZ && offset == 0 {
+		return "Z"
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	separator := ""
+	if colon {
+		separator = ":"
+	}
+	out := fmt.Sprintf("%c%02d%s%02d", sign, offset/3600, separator, offset%3600/60)
+	if seconds && offset%60 != 0 {
+		out += fmt.Sprintf("%s%02d", separator, offset%60)
+	}
+	return out
+}
+
+// formatGMTOffset renders a localized GMT offset like GMT+1 or GMT+01:00
+func formatGMTOffset(t time.Time, long bool) string {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return "GMT"
+	}
+	if long {
+		return "GMT" + formatISOOffset(t, true, false, false)
+	}
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	if offset%3600 == 0 {
+		return fmt.Sprintf("GMT%c%d", sign, offset/3600)
+	}
+	return fmt.Sprintf("GMT%c%d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+func zoneName(t time.Time) string {
+	name := t.Location().String()
+	if name == "Local" {
+		if tz, found := localZoneName(); found {
+			return tz
+		}
+		name, _ = t.Zone()
+	}
+	return name
+}
+
+func zoneCity(t time.Time) string {
+	name := zoneName(t)
+	return strings.ReplaceAll(name[strings.LastIndex(name, "/")+1:], "_", " ")
+}
+
+// localZoneName finds the IANA name of the local time zone from $TZ
+func localZoneName() (string, bool) {
+	tz, found := os.LookupEnv("TZ")
+	if !found || tz == "" {
+		return "", false
+	}
+	return strings.TrimPrefix(tz, ":"), true
+}
+
+// unicodeToGoDateFormat converts a Unicode (CLDR) date pattern to a Go layout for parsing,
+// pattern letters Go can't parse return an error
+func unicodeToGoDateFormat(s string) (string, error) {
+	tokens, err := tokenizeDatePattern(s)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, token := range tokens {
+		if token.letter == 0 {
+			sb.WriteString(token.literal)
+			continue
+		}
+		layout, found := goLayoutForToken(token)
+		if !found {
+			return "", fmt.Errorf("date pattern %s can't be used for parsing", strings.Repeat(string(token.letter), token.count))
+		}
+		sb.WriteString(layout)
+	}
+	return sb.String(), nil
+}
+
+func goLayoutForToken(token dateToken) (string, bool) {
+	n := token.count
+	switch token.letter {
+	case 'y', 'u':
+		if n == 2 {
+			return "06", true
+		}
+		return "2006", true
+	case 'M', 'L':
+		return pick(n, "1", "01", "Jan", "January")
+	case 'd':
+		return pick(n, "2", "02")
+	case 'D':
+		return "002", n == 3
+	case 'E':
+		if n == 4 {
+			return "Monday", true
+		}
+		return pick(n, "Mon", "Mon", "Mon")
+	case 'a':
+		return pick(n, "PM", "PM", "PM")
+	case 'h':
+		return pick(n, "3", "03")
+	case 'H':
+		return pick(n, "15", "15")
+	case 'm':
+		return pick(n, "4", "04")
+	case 's':
+		return pick(n, "5", "05")
+	case 'S':
+		return strings.Repeat("0", n), n <= 9
+	case 'z':
+		return pick(n, "MST", "MST", "MST")
+	case 'Z':
+		if n == 5 {
+			return "Z07:00", true
+		}
+		return pick(n, "-0700", "-0700", "-0700")
+	case 'X':
+		return pick(n, "Z07", "Z0700", "Z07:00", "Z0700", "Z07:00")
+	case 'x':
+		return pick(n, "-07", "-0700", "-07:00", "-0700", "-07:00")
+	}
+	return "", false
+}
+
+// pick returns the layout for a token repeated n times
+func pick(n int, layouts ...string) (string, bool) {
+	if n > len(layouts) {
+		return "", false
+	}
+	return layouts[n-1], true
+}
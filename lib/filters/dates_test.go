@@ -0,0 +1,251 @@
+package filters
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+func testDate(t *testing.T, zone string, hour int, minute int) time.Time {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(2021, time.March, 7, hour, minute, 9, 123456789, loc)
+}
+
+func TestFormatUnicodeDate(t *testing.T) {
+	// Sunday March 7th 2021 14:05:09.123456789 in New York (EST, UTC-5), ISO week 9
+	date := testDate(t, "America/New_York", 14, 5)
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"G", "AD"}, {"GG", "AD"}, {"GGG", "AD"}, {"GGGG", "Anno Domini"}, {"GGGGG", "A"},
+		{"y", "2021"}, {"yy", "21"}, {"yyy", "2021"}, {"yyyy", "2021"}, {"yyyyy", "02021"},
+		{"u", "2021"}, {"uu", "21"}, {"uuuu", "2021"},
+		{"Y", "2021"}, {"YY", "21"}, {"YYYY", "2021"},
+		{"Q", "1"}, {"QQ", "01"}, {"QQQ", "Q1"}, {"QQQQ", "1st quarter"}, {"QQQQQ", "1"},
+		{"q", "1"}, {"qq", "01"}, {"qqq", "Q1"}, {"qqqq", "1st quarter"}, {"qqqqq", "1"},
+		{"M", "3"}, {"MM", "03"}, {"MMM", "Mar"}, {"MMMM", "March"}, {"MMMMM", "M"},
+		{"L", "3"}, {"LL", "03"}, {"LLL", "Mar"}, {"LLLL", "March"}, {"LLLLL", "M"},
+		{"w", "9"}, {"ww", "09"},
+		{"W", "1"},
+		{"d", "7"}, {"dd", "07"},
+		{"D", "66"}, {"DD", "66"}, {"DDD", "066"},
+		{"F", "1"},
+		{"E", "Sun"}, {"EE", "Sun"}, {"EEE", "Sun"}, {"EEEE", "Sunday"}, {"EEEEE", "S"}, {"EEEEEE", "Su"},
+		{"e", "7"}, {"ee", "07"}, {"eee", "Sun"}, {"eeee", "Sunday"}, {"eeeee", "S"}, {"eeeeee", "Su"},
+		{"c", "7"}, {"cc", "07"}, {"ccc", "Sun"}, {"cccc", "Sunday"}, {"ccccc", "S"}, {"cccccc", "Su"},
+		{"a", "PM"}, {"aa", "PM"}, {"aaa", "PM"}, {"aaaa", "PM"}, {"aaaaa", "p"},
+		{"b", "PM"}, {"bbbb", "PM"}, {"bbbbb", "p"},
+		{"B", "in the afternoon"}, {"BBBB", "in the afternoon"},
+		{"h", "2"}, {"hh", "02"},
+		{"H", "14"}, {"HH", "14"},
+		{"K", "2"}, {"KK", "02"},
+		{"k", "14"}, {"kk", "14"},
+		{"m", "5"}, {"mm", "05"},
+		{"s", "9"}, {"ss", "09"},
+		{"S", "1"}, {"SS", "12"}, {"SSS", "123"}, {"SSSSSS", "123456"}, {"SSSSSSSSS", "123456789"}, {"SSSSSSSSSS", "1234567890"},
+		{"A", "50709123"}, {"AAAAAAAAA", "050709123"},
+		{"z", "EST"}, {"zz", "EST"}, {"zzz", "EST"}, {"zzzz", "GMT-05:00"},
+		{"Z", "-0500"}, {"ZZ", "-0500"}, {"ZZZ", "-0500"}, {"ZZZZ", "GMT-05:00"}, {"ZZZZZ", "-05:00"},
+		{"O", "GMT-5"}, {"OOOO", "GMT-05:00"},
+		{"v", "EST"}, {"vvvv", "New York Time"},
+		{"V", "unk"}, {"VV", "America/New_York"}, {"VVV", "New York"}, {"VVVV", "New York Time"},
+		{"X", "-05"}, {"XX", "-0500"}, {"XXX", "-05:00"}, {"XXXX", "-0500"}, {"XXXXX", "-05:00"},
+		{"x", "-05"}, {"xx", "-0500"}, {"xxx", "-05:00"}, {"xxxx", "-0500"}, {"xxxxx", "-05:00"},
+		// literals, quoting and unknown letters
+		{"yyyy-MM-dd 'at' HH:mm", "2021-03-07 at 14:05"},
+		{"h 'o''clock'", "2 o'clock"},
+		{"''", "'"},
+		{"jj", "jj"},
+	}
+	for _, test := range tests {
+		got, err := formatUnicodeDate(date, test.pattern)
+		if err != nil {
+			t.Errorf("formatUnicodeDate(%q) returned error %v", test.pattern, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("formatUnicodeDate(%q) = %q, want %q", test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestFormatUnicodeDateSpecialCases(t *testing.T) {
+	utc := testDate(t, "UTC", 14, 5)
+	midnight := time.Date(2021, time.March, 7, 0, 0, 0, 0, time.UTC)
+	noon := time.Date(2021, time.March, 7, 12, 0, 0, 0, time.UTC)
+	india := testDate(t, "Asia/Kolkata", 9, 5)
+	tests := []struct {
+		name    string
+		date    time.Time
+		pattern string
+		want    string
+	}{
+		{"X uses Z for UTC", utc, "X", "Z"},
+		{"x never uses Z", utc, "x", "+00"},
+		{"ZZZZZ uses Z for UTC", utc, "ZZZZZ", "Z"},
+		{"O for UTC", utc, "O", "GMT"},
+		{"b at midnight", midnight, "b", "midnight"},
+		{"b at noon", noon, "b", "noon"},
+		{"a at midnight", midnight, "a", "AM"},
+		{"h at midnight", midnight, "h", "12"},
+		{"K at noon", noon, "K", "0"},
+		{"k at midnight", midnight, "k", "24"},
+		{"B in the morning", india, "B", "in the morning"},
+		{"B at night", midnight, "B", "at night"},
+		{"half hour offset X", india, "X", "+0530"},
+		{"half hour offset O", india, "O", "GMT+5:30"},
+		{"BC era", time.Date(-43, time.March, 15, 0, 0, 0, 0, time.UTC), "G GGGG", "BC Before Christ"},
+		{"last quarter", time.Date(2021, time.December, 31, 0, 0, 0, 0, time.UTC), "QQQ QQQQ", "Q4 4th quarter"},
+		{"ISO week year differs", time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), "YYYY-'W'ww yyyy", "2020-W53 2021"},
+	}
+	for _, test := range tests {
+		got, err := formatUnicodeDate(test.date, test.pattern)
+		if err != nil {
+			t.Errorf("%s: returned error %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: formatUnicodeDate(%q) = %q, want %q", test.name, test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestFormatUnicodeDateUnterminatedQuote(t *testing.T) {
+	if _, err := formatUnicodeDate(time.Now(), "yyyy 'at"); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+func TestUnicodeToGoDateFormat(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"yyyy-MM-dd HH:mm:ss", "2006-01-02 15:04:05"},
+		{"yy/M/d h:m:s a", "06/1/2 3:4:5 PM"},
+		{"EEEE, MMMM d", "Monday, January 2"},
+		{"EEE MMM dd", "Mon Jan 02"},
+		{"DDD", "002"},
+		{"HH:mm:ss.SSS", "15:04:05.000"},
+		{"z Z ZZZZZ", "MST -0700 Z07:00"},
+		{"X XX XXX", "Z07 Z0700 Z07:00"},
+		{"x xx xxx", "-07 -0700 -07:00"},
+		{"'T'HH", "T15"},
+	}
+	for _, test := range tests {
+		got, err := unicodeToGoDateFormat(test.pattern)
+		if err != nil {
+			t.Errorf("unicodeToGoDateFormat(%q) returned error %v", test.pattern, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("unicodeToGoDateFormat(%q) = %q, want %q", test.pattern, got, test.want)
+		}
+	}
+	for _, pattern := range []string{"QQQ", "w", "B", "VV", "MMMMM"} {
+		if _, err := unicodeToGoDateFormat(pattern); err == nil {
+			t.Errorf("unicodeToGoDateFormat(%q) should not be usable for parsing", pattern)
+		}
+	}
+}
+
+func TestTzFilter(t *testing.T) {
+	date := testDate(t, "America/New_York", 14, 5)
+	tests := []struct {
+		zone string
+		want string
+	}{
+		{"UTC", "2021-03-07 19:05 UTC"},
+		{"Asia/Tokyo", "2021-03-08 04:05 Asia/Tokyo"},
+		{"America/New_York", "2021-03-07 14:05 America/New_York"},
+		{"Asia/Kolkata", "2021-03-08 00:35 Asia/Kolkata"},
+	}
+	for _, test := range tests {
+		out, perr := tzFilter(pongo2.AsValue(date), pongo2.AsValue(test.zone))
+		if perr != nil {
+			t.Errorf("tz:%q returned error %v", test.zone, perr)
+			continue
+		}
+		got, err := formatUnicodeDate(out.Interface().(time.Time), "yyyy-MM-dd HH:mm VV")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("tz:%q = %q, want %q", test.zone, got, test.want)
+		}
+	}
+	if _, perr := tzFilter(pongo2.AsValue(date), pongo2.AsValue("Nowhere/Nope")); perr == nil {
+		t.Error("tz should fail for an unknown time zone")
+	}
+	if _, perr := tzFilter(pongo2.AsValue("2021-03-07"), pongo2.AsValue("UTC")); perr == nil {
+		t.Error("tz should fail for input that isn't a time")
+	}
+}
+
+func TestDateAndTzInTemplates(t *testing.T) {
+	date := testDate(t, "America/New_York", 14, 5)
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{ d|date:"yyyy-MM-dd" }}`, "2021-03-07"},
+		{`{{ d|tz:"UTC"|date:"HH:mm X" }}`, "19:05 Z"},
+		{`{{ "2021-03-07 14:05:09"|parseDate|date:"EEEE HH:mm" }}`, "Sunday 14:05"},
+		{`{{ "07/03/2021"|parseDate:"dd/MM/yyyy"|date:"MMMM d" }}`, "March 7"},
+		{`{{ "3/7/2021"|parseDate:"us"|date:"MMMM d" }}`, "March 7"},
+	}
+	for _, test := range tests {
+		tpl, err := pongo2.FromString(test.template)
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		got, err := tpl.Execute(pongo2.Context{"d": date})
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		if strings.TrimSpace(got) != test.want {
+			t.Errorf("%s = %q, want %q", test.template, got, test.want)
+		}
+	}
+}
+
+func TestParseDateUsesParseLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetParseLocation(loc)
+	defer SetParseLocation(time.Local)
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{ "2021-03-07 14:05"|parseDate|date:"HH:mm" }}`, "14:05"},
+		{`{{ "2021-03-07 14:05"|parseDate|date:"HH:mm VV" }}`, "14:05 America/New_York"},
+		{`{{ "2021-03-07 14:05"|parseDate|tz:"UTC"|date:"HH:mm" }}`, "19:05"},
+		{`{{ "2021-03-07T14:05:09Z"|parseDate|date:"HH:mm X" }}`, "14:05 Z"},
+	}
+	for _, test := range tests {
+		tpl, err := pongo2.FromString(test.template)
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		got, err := tpl.Execute(pongo2.Context{})
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %q, want %q", test.template, got, test.want)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"errors"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/dlclark/regexp2"
 	"github.com/flosch/pongo2/v4"
@@ -13,22 +12,52 @@ import (
 )
 
 func init() {
+	// outputs are file names, not HTML
+	pongo2.SetAutoescape(false)
 	pongo2.ReplaceFilter("date", DateFilter)
 	pongo2.ReplaceFilter("time", DateFilter)
+	pongo2.RegisterFilter("parseDate", parseDateFilter)
+	pongo2.RegisterFilter("tz", tzFilter)
 	pongo2.ReplaceFilter("title", titleFilter)
-	pongo2.RegisterFilter("pascal", titleFilter)
+	pongo2.RegisterFilter("sentence", sentenceFilter)
+	pongo2.RegisterFilter("pascal", pascalFilter)
 	pongo2.RegisterFilter("snake", snakeFilter)
 	pongo2.RegisterFilter("camel", camelFilter)
 	pongo2.RegisterFilter("kebab", kebabFilter)
+	pongo2.RegisterFilter("ascii", asciiFilter)
+	pongo2.RegisterFilter("slug", slugFilter)
+	pongo2.RegisterFilter("stripDiacritics", stripDiacriticsFilter)
+	pongo2.RegisterFilter("nfc", nfcFilter)
+	pongo2.RegisterFilter("nfd", nfdFilter)
+	pongo2.ReplaceFilter("safe", safeFilter)
 	pongo2.RegisterFilter("replace", replaceFilter)
 	pongo2.RegisterFilter("regexReplace", regexReplaceFilter)
+	pongo2.RegisterFilter("translate", translateFilter)
 	pongo2.RegisterFilter("with", withFilter)
 	pongo2.RegisterFilter("match", matchFilter)
 	pongo2.RegisterFilter("index", indexFilter)
 	pongo2.RegisterFilter("pad", padFilter)
+	pongo2.RegisterFilter("truncate", truncateFilter)
+	pongo2.RegisterFilter("truncateBytes", truncateBytesFilter)
+	pongo2.RegisterFilter("ellipsis", ellipsisFilter)
+	pongo2.RegisterFilter("left", leftFilter)
+	pongo2.RegisterFilter("right", rightFilter)
+	pongo2.RegisterFilter("bytes", bytesFilter)
+	pongo2.RegisterFilter("ago", agoFilter)
+	pongo2.RegisterFilter("words", wordsFilter)
+	pongo2.RegisterFilter("roman", romanFilter)
+	pongo2.RegisterFilter("ordinal", ordinalFilter)
+	pongo2.ReplaceFilter("add", addFilter)
+	pongo2.RegisterFilter("sub", arithmeticFilter("sub", sub))
+	pongo2.RegisterFilter("mul", arithmeticFilter("mul", mul))
+	pongo2.RegisterFilter("div", divFilter)
+	pongo2.RegisterFilter("mod", arithmeticFilter("mod", mod))
+	pongo2.RegisterFilter("round", roundFilter)
+	pongo2.RegisterFilter("int", intFilter)
+	pongo2.RegisterFilter("format", formatFilter)
 }
 
-func titleFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+func pascalFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
 	if !in.IsString() {
 		return pongo2.AsValue(""), nil
 	}
@@ -56,65 +85,6 @@ func kebabFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.
 	return pongo2.AsValue(strcase.ToKebab(in.String())), nil
 }
 
-func DateFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
-	t, isTime := in.Interface().(time.Time)
-	if !isTime {
-		return nil, &pongo2.Error{
-			Sender:    "filter:date",
-			OrigError: errors.New("filter input argument must be of type 'time.Time'"),
-		}
-	}
-	//fmt.Println(unicodeToGoDateFormat(param.String()))
-	return pongo2.AsValue(t.Format(unicodeToGoDateFormat(param.String()))), nil
-}
-
-func unicodeToGoDateFormat(s string) string {
-	mRegex := regexp2.MustCompile(`(?<![AP])M`, 0)
-	eeeRegex := regexp2.MustCompile(`E{1,3}`, 0)
-	aRegex := regexp2.MustCompile(`(?<!J)a{1,4}(?!n)`, 0)
-
-	ret := strings.ReplaceAll(s, "yyyy", "2006")
-	ret = strings.ReplaceAll(ret, "yyy", "006")
-	ret = strings.ReplaceAll(ret, "yy", "06")
-	ret = strings.ReplaceAll(ret, "MMMM", "January")
-	ret = strings.ReplaceAll(ret, "MMM", "Jan")
-	ret = strings.ReplaceAll(ret, "MM", "01")
-	ret = strings.ReplaceAll(ret, "dd", "02")
-	ret = strings.ReplaceAll(ret, "d", "2")
-	ret = strings.ReplaceAll(ret, "EEEE", "Monday")
-	ret, _ = eeeRegex.Replace(ret, "Mon", -1, -1)
-	ret = strings.ReplaceAll(ret, "HH", "15")
-	ret = strings.ReplaceAll(ret, "H", "15")
-	ret = strings.ReplaceAll(ret, "hh", "03")
-	ret = strings.ReplaceAll(ret, "h", "3")
-	ret = strings.ReplaceAll(ret, "mm", "04")
-	ret = strings.ReplaceAll(ret, "m", "4")
-	ret = strings.ReplaceAll(ret, "ss", "05")
-	ret = strings.ReplaceAll(ret, "s", "5")
-	ret, _ = mRegex.Replace(ret, "1", -1, -1)
-	ret, _ = aRegex.Replace(ret, "PM", -1, -1)
-	return ret
-}
-
-func replaceFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
-	if !in.IsString() {
-		return pongo2.AsValue(""), nil
-	}
-	return pongo2.AsValue(strings.ReplaceAll(in.String(), param.String(), "--REPLACEME--")), nil
-}
-
-func regexReplaceFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
-	if !in.IsString() {
-		return pongo2.AsValue(""), nil
-	}
-	re := regexp2.MustCompile(param.String(), 0)
-	out, err := re.Replace(in.String(), "--REPLACEME--", -1, -1)
-	if err != nil {
-		return pongo2.AsValue(""), nil
-	}
-	return pongo2.AsValue(out), nil
-}
-
 func withFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
 	return pongo2.AsValue(strings.ReplaceAll(in.String(), "--REPLACEME--", param.String())), nil
 }
@@ -123,7 +93,10 @@ func matchFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.
 	if !in.IsString() {
 		return pongo2.AsValue(""), nil
 	}
-	re := regexp2.MustCompile(param.String(), 0)
+	re, err := regexp2.Compile(param.String(), 0)
+	if err != nil {
+		return nil, &pongo2.Error{Sender: "filter:match", OrigError: err}
+	}
 	var matches []string
 	m, _ := re.FindStringMatch(in.String())
 	for m != nil {
@@ -154,3 +127,18 @@ func indexFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.
 func padFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
 	return pongo2.AsValue(util.ZeroPadString(in.String(), param.String())), nil
 }
+
+func safeFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	platform := "windows" // the strictest rules are the most portable
+	if !param.IsNil() {
+		platform = param.String()
+	}
+	if util.IndexOf(platform, util.PortablePlatforms) == -1 {
+		return nil, &pongo2.Error{
+			Sender:    "filter:safe",
+			OrigError: errors.New("platform must be one of " + strings.Join(util.PortablePlatforms, ", ")),
+		}
+	}
+	out, _ := util.SanitizeComponent(in.String(), platform)
+	return pongo2.AsValue(out), nil
+}
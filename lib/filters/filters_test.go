@@ -0,0 +1,36 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+// outputs are file names, so characters HTML would escape must come out unchanged
+func TestTemplatesAreNotAutoescaped(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{ name }}`, `Tom & Jerry's <Best> "Cuts"`},
+		{`{{ name|upper }}`, `TOM & JERRY'S <BEST> "CUTS"`},
+		{`{{ name|escape }}`, `Tom &amp; Jerry&#39;s &lt;Best&gt; &quot;Cuts&quot;`},
+		{`{{ name|safe }}`, `Tom & Jerry's _Best_ _Cuts_`},
+		{`{{ name|safe:"posix" }}`, `Tom & Jerry's <Best> "Cuts"`},
+	}
+	for _, test := range tests {
+		tpl, err := pongo2.FromString(test.template)
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		got, err := tpl.Execute(pongo2.Context{"name": `Tom & Jerry's <Best> "Cuts"`})
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %q, want %q", test.template, got, test.want)
+		}
+	}
+}
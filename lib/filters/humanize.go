@@ -0,0 +1,190 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flosch/pongo2/v4"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+var (
+	numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tensWords     = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scaleWords    = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+	romanNumerals = []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"}, {100, "C"}, {90, "XC"},
+		{50, "L"}, {40, "XL"}, {10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	}
+	agoUnits = []struct {
+		duration time.Duration
+		name     string
+	}{
+		{365 * 24 * time.Hour, "year"}, {30 * 24 * time.Hour, "month"}, {7 * 24 * time.Hour, "week"},
+		{24 * time.Hour, "day"}, {time.Hour, "hour"}, {time.Minute, "minute"}, {time.Second, "second"},
+	}
+)
+
+// toInt accepts integers as well as strings of digits, such as regex captures
+func toInt(in *pongo2.Value, sender string) (int, *pongo2.Error) {
+	if in.IsInteger() {
+		return in.Integer(), nil
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(in.String()))
+	if err != nil {
+		return 0, &pongo2.Error{
+			Sender:    sender,
+			OrigError: errors.New("filter input '" + in.String() + "' is not an integer"),
+		}
+	}
+	return i, nil
+}
+
+func bytesFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	size, perr := toInt(in, "filter:bytes")
+	if perr != nil {
+		return nil, perr
+	}
+	iec := false
+	switch param.String() {
+	case "", "si":
+	case "iec":
+		iec = true
+	default:
+		return nil, &pongo2.Error{
+			Sender:    "filter:bytes",
+			OrigError: errors.New("filter argument must be 'si' or 'iec'"),
+		}
+	}
+	return pongo2.AsValue(util.FormatBytes(int64(size), iec)), nil
+}
+
+func agoFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	t, isTime := in.Interface().(time.Time)
+	if !isTime {
+		return nil, &pongo2.Error{
+			Sender:    "filter:ago",
+			OrigError: errors.New("filter input argument must be of type 'time.Time'"),
+		}
+	}
+	diff := time.Since(t)
+	future := diff < 0
+	if future {
+		diff = -diff
+	}
+	for _, u := range agoUnits {
+		if diff >= u.duration {
+			count := int(diff / u.duration)
+			name := u.name
+			if count != 1 {
+				name += "s"
+			}
+			if future {
+				return pongo2.AsValue(fmt.Sprintf("in %d %s", count, name)), nil
+			}
+			return pongo2.AsValue(fmt.Sprintf("%d %s ago", count, name)), nil
+		}
+	}
+	return pongo2.AsValue("just now"), nil
+}
+
+func numberWordsFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	i, err := toInt(in, "filter:words")
+	if err != nil {
+		return nil, err
+	}
+	return pongo2.AsValue(intToWords(i)), nil
+}
+
+func romanFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	i, err := toInt(in, "filter:roman")
+	if err != nil {
+		return nil, err
+	}
+	if i < 1 || i > 3999 {
+		return nil, &pongo2.Error{
+			Sender:    "filter:roman",
+			OrigError: errors.New("filter input must be between 1 and 3999"),
+		}
+	}
+	var sb strings.Builder
+	for _, r := range romanNumerals {
+		for i >= r.value {
+			sb.WriteString(r.symbol)
+			i -= r.value
+		}
+	}
+	return pongo2.AsValue(sb.String()), nil
+}
+
+func ordinalFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	i, err := toInt(in, "filter:ordinal")
+	if err != nil {
+		return nil, err
+	}
+	suffix := "th"
+	switch abs := int(math.Abs(float64(i))); {
+	case abs%100 >= 11 && abs%100 <= 13:
+	case abs%10 == 1:
+		suffix = "st"
+	case abs%10 == 2:
+		suffix = "nd"
+	case abs%10 == 3:
+		suffix = "rd"
+	}
+	return pongo2.AsValue(fmt.Sprintf("%d%s", i, suffix)), nil
+}
+
+func intToWords(i int) string {
+	if i < 0 { // -(i+1)+1 avoids overflowing on the smallest int, which has no positive counterpart
+		return "minus " + magnitudeToWords(uint64(-(i+1))+1)
+	}
+	return magnitudeToWords(uint64(i))
+}
+
+func magnitudeToWords(n uint64) string {
+	if n < 20 {
+		return numberWords[n]
+	}
+	groups := []string{}
+	for scale := 0; n > 0; scale++ {
+		group := int(n % 1000)
+		n /= 1000
+		if group == 0 {
+			continue
+		}
+		words := hundredsToWords(group)
+		if scaleWords[scale] != "" {
+			words += " " + scaleWords[scale]
+		}
+		groups = append([]string{words}, groups...)
+	}
+	return strings.Join(groups, " ")
+}
+
+func hundredsToWords(i int) string {
+	parts := []string{}
+	if i >= 100 {
+		parts = append(parts, numberWords[i/100]+" hundred")
+		i %= 100
+	}
+	if i >= 20 {
+		tens := tensWords[i/10]
+		if i%10 != 0 {
+			tens += "-" + numberWords[i%10]
+		}
+		parts = append(parts, tens)
+	} else if i > 0 {
+		parts = append(parts, numberWords[i])
+	}
+	return strings.Join(parts, " ")
+}
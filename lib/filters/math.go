@@ -0,0 +1,186 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+type arithmeticOperation func(a float64, b float64) (float64, error)
+
+// toNumber accepts numbers as well as numeric strings, such as regex captures, and reports whether the value is whole
+func toNumber(in *pongo2.Value, sender string) (float64, bool, *pongo2.Error) {
+	if in.IsInteger() {
+		return float64(in.Integer()), true, nil
+	}
+	if in.IsFloat() {
+		return in.Float(), false, nil
+	}
+	s := strings.TrimSpace(in.String())
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return float64(i), true, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, &pongo2.Error{
+			Sender:    sender,
+			OrigError: errors.New("'" + in.String() + "' is not a number"),
+		}
+	}
+	return f, false, nil
+}
+
+// numberValue keeps whole numbers as integers and formats fractions without trailing zeros
+func numberValue(f float64, whole bool) *pongo2.Value {
+	if whole && f == math.Trunc(f) {
+		return pongo2.AsValue(int(f))
+	}
+	return pongo2.AsValue(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func arithmeticFilter(name string, fn arithmeticOperation) pongo2.FilterFunction {
+	sender := "filter:" + name
+	return func(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+		a, aWhole, perr := toNumber(in, sender)
+		if perr != nil {
+			return nil, perr
+		}
+		b, bWhole, perr := toNumber(param, sender)
+		if perr != nil {
+			return nil, perr
+		}
+		out, err := fn(a, b)
+		if err != nil {
+			return nil, &pongo2.Error{Sender: sender, OrigError: err}
+		}
+		return numberValue(out, aWhole && bWhole), nil
+	}
+}
+
+// addFilter adds numbers and, like pongo2's builtin add, joins two strings when either isn't numeric.
+// Mixing a number with a non-numeric string is an error like it is for the other arithmetic filters.
+func addFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	_, _, inErr := toNumber(in, "filter:add")
+	_, _, paramErr := toNumber(param, "filter:add")
+	if (inErr != nil || paramErr != nil) && in.IsString() && param.IsString() {
+		return pongo2.AsValue(in.String() + param.String()), nil
+	}
+	return arithmeticFilter("add", add)(in, param)
+}
+
+func add(a float64, b float64) (float64, error) {
+	return a + b, nil
+}
+
+func sub(a float64, b float64) (float64, error) {
+	return a - b, nil
+}
+
+func mul(a float64, b float64) (float64, error) {
+	return a * b, nil
+}
+
+func div(a float64, b float64) (float64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+func mod(a float64, b float64) (float64, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return math.Mod(a, b), nil
+}
+
+func divFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	a, _, perr := toNumber(in, "filter:div")
+	if perr != nil {
+		return nil, perr
+	}
+	b, _, perr := toNumber(param, "filter:div")
+	if perr != nil {
+		return nil, perr
+	}
+	out, err := div(a, b)
+	if err != nil {
+		return nil, &pongo2.Error{Sender: "filter:div", OrigError: err}
+	}
+	// an even division stays whole, otherwise the fraction is kept
+	return numberValue(out, true), nil
+}
+
+func roundFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	f, _, err := toNumber(in, "filter:round")
+	if err != nil {
+		return nil, err
+	}
+	places := 0
+	if !param.IsNil() {
+		places, err = toInt(param, "filter:round")
+		if err != nil {
+			return nil, err
+		}
+	}
+	shift := math.Pow(10, float64(places))
+	return numberValue(math.Round(f*shift)/shift, places <= 0), nil
+}
+
+func intFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	f, _, err := toNumber(in, "filter:int")
+	if err != nil {
+		return nil, err
+	}
+	return pongo2.AsValue(int(math.Trunc(f))), nil
+}
+
+func formatFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	f, whole, err := toNumber(in, "filter:format")
+	if err != nil {
+		return nil, err
+	}
+	// pass the value as the type the verb expects
+	var value interface{}
+	switch verb := formatVerb(param.String()); {
+	case strings.ContainsRune("eEfFgG", verb):
+		value = f
+	case strings.ContainsRune("sqv", verb):
+		value = in.String()
+	case whole:
+		value = int(f)
+	default:
+		value = f
+	}
+	out := fmt.Sprintf(param.String(), value)
+	if strings.Contains(out, "%!") {
+		return nil, &pongo2.Error{
+			Sender:    "filter:format",
+			OrigError: errors.New("invalid format '" + param.String() + "' for " + in.String()),
+		}
+	}
+	return pongo2.AsValue(out), nil
+}
+
+// formatVerb finds the verb of the first printf directive in a format string
+func formatVerb(format string) rune {
+	directive := false
+	for _, r := range format {
+		if !directive {
+			directive = r == '%'
+			continue
+		}
+		if r == '%' { // escaped percent sign
+			directive = false
+			continue
+		}
+		if !strings.ContainsRune("+-# 0123456789.", r) {
+			return r
+		}
+	}
+	return 0
+}
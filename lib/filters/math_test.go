@@ -0,0 +1,59 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+func TestArithmeticFilters(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`{{ 2|add:3 }}`, "5"},
+		{`{{ "2"|add:"3" }}`, "5"},
+		{`{{ 1.5|add:1 }}`, "2.5"},
+		{`{{ f|add:"-x" }}`, "photo-x"},
+		{`{{ "a"|add:"b" }}`, "ab"},
+		{`{{ 5|sub:2 }}`, "3"},
+		{`{{ "07"|sub:1 }}`, "6"},
+		{`{{ 3|mul:"4" }}`, "12"},
+		{`{{ 7|div:2 }}`, "3.5"},
+		{`{{ 7|mod:4 }}`, "3"},
+	}
+	for _, test := range tests {
+		tpl, err := pongo2.FromString(test.template)
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		got, err := tpl.Execute(pongo2.Context{"f": "photo"})
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %q, want %q", test.template, got, test.want)
+		}
+	}
+}
+
+func TestArithmeticFiltersRejectNonNumbers(t *testing.T) {
+	for _, template := range []string{
+		`{{ "abc"|add:1 }}`,
+		`{{ 1|add:"abc" }}`,
+		`{{ "abc"|sub:1 }}`,
+		`{{ 2|mul:"x" }}`,
+		`{{ 1|div:0 }}`,
+		`{{ 1|mod:0 }}`,
+	} {
+		tpl, err := pongo2.FromString(template)
+		if err != nil {
+			continue // rejected while parsing
+		}
+		if got, err := tpl.Execute(pongo2.Context{}); err == nil {
+			t.Errorf("%s = %q, want an error", template, got)
+		}
+	}
+}
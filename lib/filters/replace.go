@@ -0,0 +1,150 @@
+package filters
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+	"github.com/flosch/pongo2/v4"
+)
+
+var regexFlags = map[rune]regexp2.RegexOptions{
+	'i': regexp2.IgnoreCase,
+	'm': regexp2.Multiline,
+	's': regexp2.Singleline,
+	'x': regexp2.IgnorePatternWhitespace,
+	'g': 0, // replacing every match is already the default
+}
+
+// replaceFilter replaces text literally, with "s/old/new/flags" or with the with filter
+func replaceFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	old, replacement, flags, explicit := parseReplaceArgument(param.String())
+	if !explicit {
+		return pongo2.AsValue(strings.ReplaceAll(in.String(), param.String(), "--REPLACEME--")), nil
+	}
+	out, err := regexReplace(in.String(), regexp2.Escape(old), strings.ReplaceAll(replacement, "$", "$$"), flags)
+	if err != nil {
+		return nil, &pongo2.Error{Sender: "filter:replace", OrigError: err}
+	}
+	return pongo2.AsValue(out), nil
+}
+
+// regexReplaceFilter replaces regex matches with "s/pattern/replacement/flags", where the replacement
+// can use $1 and ${name} backreferences, or with the with filter
+func regexReplaceFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	pattern, replacement, flags, explicit := parseReplaceArgument(param.String())
+	if !explicit {
+		pattern, replacement, flags = param.String(), "--REPLACEME--", ""
+	}
+	out, err := regexReplace(in.String(), pattern, replacement, flags)
+	if err != nil {
+		return nil, &pongo2.Error{Sender: "filter:regexReplace", OrigError: err}
+	}
+	return pongo2.AsValue(out), nil
+}
+
+// translateFilter replaces several strings at once from a list of pairs like "ä=ae,ö=oe",
+// a backslash escapes a literal comma, equals sign or backslash
+func translateFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	pairs := [][]string{}
+	for _, pair := range splitUnescaped(param.String(), ',') {
+		if pair == "" {
+			continue
+		}
+		parts := splitUnescaped(pair, '=')
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, &pongo2.Error{
+				Sender:    "filter:translate",
+				OrigError: errors.New("'" + pair + "' is not a from=to pair"),
+			}
+		}
+		pairs = append(pairs, []string{unescape(parts[0]), unescape(parts[1])})
+	}
+	// the longest match wins when pairs overlap
+	sort.SliceStable(pairs, func(i, j int) bool { return len(pairs[i][0]) > len(pairs[j][0]) })
+	oldnew := []string{}
+	for _, pair := range pairs {
+		oldnew = append(oldnew, pair...)
+	}
+	return pongo2.AsValue(strings.NewReplacer(oldnew...).Replace(in.String())), nil
+}
+
+func regexReplace(s string, pattern string, replacement string, flags string) (string, error) {
+	var options regexp2.RegexOptions
+	for _, f := range flags {
+		option, found := regexFlags[f]
+		if !found {
+			return s, errors.New("unknown regex flag '" + string(f) + "'")
+		}
+		options |= option
+	}
+	re, err := regexp2.Compile(pattern, options)
+	if err != nil {
+		return s, err
+	}
+	return re.Replace(s, replacement, -1, -1)
+}
+
+// parseReplaceArgument splits a sed style "s/pattern/replacement/flags" argument, an argument in any other
+// form (including paths like "/tmp/x/") is left for the with filter
+func parseReplaceArgument(arg string) (pattern string, replacement string, flags string, ok bool) {
+	if !strings.HasPrefix(arg, "s/") {
+		return "", "", "", false
+	}
+	parts := splitUnescaped(arg[2:], '/')
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return unescapeRune(parts[0], '/'), unescapeRune(parts[1], '/'), parts[2], true
+}
+
+// splitUnescaped splits a string on a separator that isn't preceded by a backslash, escapes are kept
+func splitUnescaped(s string, sep rune) []string {
+	parts := []string{}
+	var current strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	return append(parts, current.String())
+}
+
+// unescapeRune removes the backslash in front of an escaped separator, other escapes are left for the regex
+func unescapeRune(s string, sep rune) string {
+	return strings.ReplaceAll(s, "\\"+string(sep), string(sep))
+}
+
+// unescape removes every escaping backslash
+func unescape(s string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+func TestReplaceFilters(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		// a single argument is always literal, even when it contains slashes
+		{`{{ "/tmp/x/a"|replace:"/tmp/x/"|with:"" }}`, "a"},
+		{`{{ "/tmp/x/"|replace:"/tmp/x/"|with:"" }}`, ""},
+		{`{{ "a/b/c/d"|replace:"/b/c/"|with:"" }}`, "ad"},
+		{`{{ "a/b/c/d"|replace:"/b/"|with:"/" }}`, "a/c/d"},
+		{`{{ "/x/y/z"|regexReplace:"/x/y/"|with:"" }}`, "z"},
+		{`{{ "2021/03/07"|regexReplace:"/\\d\\d/"|with:"" }}`, "202107"},
+		// the sed style form needs a leading s
+		{`{{ "a.b.c"|replace:"s/./-/" }}`, "a-b-c"},
+		{`{{ "A-a"|replace:"s/a/b/i" }}`, "b-b"},
+		{`{{ "/tmp/x"|replace:"s/\\/tmp\\//\\/var\\//" }}`, "/var/x"},
+		{`{{ "IMG_1234"|regexReplace:"s/IMG_(\\d+)/photo-$1/" }}`, "photo-1234"},
+		{`{{ "IMG_1234"|regexReplace:"IMG_"|with:"photo-" }}`, "photo-1234"},
+	}
+	for _, test := range tests {
+		tpl, err := pongo2.FromString(test.template)
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		got, err := tpl.Execute(pongo2.Context{})
+		if err != nil {
+			t.Errorf("%s: %v", test.template, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s = %q, want %q", test.template, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package filters
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/flosch/pongo2/v4"
+)
+
+func truncateFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	n, err := toInt(param, "filter:truncate")
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(in.String())
+	if len(runes) <= n || n < 0 {
+		return pongo2.AsValue(in.String()), nil
+	}
+	return pongo2.AsValue(strings.TrimRightFunc(string(runes[:n]), unicode.IsSpace)), nil
+}
+
+func truncateBytesFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	n, err := toInt(param, "filter:truncateBytes")
+	if err != nil {
+		return nil, err
+	}
+	s := in.String()
+	if len(s) <= n || n < 0 {
+		return pongo2.AsValue(s), nil
+	}
+	// back up to the start of the character that doesn't fit
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return pongo2.AsValue(s[:n]), nil
+}
+
+func ellipsisFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	n, err := toInt(param, "filter:ellipsis")
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(in.String())
+	if len(runes) <= n || n < 1 {
+		return pongo2.AsValue(in.String()), nil
+	}
+	return pongo2.AsValue(strings.TrimRightFunc(string(runes[:n-1]), unicode.IsSpace) + "…"), nil
+}
+
+func leftFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	n, err := toInt(param, "filter:left")
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(in.String())
+	if len(runes) <= n || n < 0 {
+		return pongo2.AsValue(in.String()), nil
+	}
+	return pongo2.AsValue(string(runes[:n])), nil
+}
+
+func rightFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	n, err := toInt(param, "filter:right")
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(in.String())
+	if len(runes) <= n || n < 0 {
+		return pongo2.AsValue(in.String()), nil
+	}
+	return pongo2.AsValue(string(runes[len(runes)-n:])), nil
+}
+
+// wordsFilter keeps the first n words when given an argument, otherwise it spells out a number
+func wordsFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if param.IsNil() {
+		return numberWordsFilter(in, param)
+	}
+	n, err := toInt(param, "filter:words")
+	if err != nil {
+		return nil, err
+	}
+	s := in.String()
+	count := 0
+	inWord := false
+	for i, r := range s {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		if !inWord {
+			if count == n {
+				return pongo2.AsValue(strings.TrimRightFunc(s[:i], unicode.IsSpace)), nil
+			}
+			count++
+			inWord = true
+		}
+	}
+	return pongo2.AsValue(s), nil
+}
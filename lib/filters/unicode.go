@@ -0,0 +1,109 @@
+package filters
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/flosch/pongo2/v4"
+	"github.com/mozillazg/go-unidecode"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations that differ from the generic unidecode table
+var transliterationOverrides = map[rune]string{
+	'Ä': "Ae",
+	'Ö': "Oe",
+	'Ü': "Ue",
+	'ä': "ae",
+	'ö': "oe",
+	'ü': "ue",
+	'ß': "ss",
+}
+
+func asciiFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	return pongo2.AsValue(toASCII(in.String())), nil
+}
+
+func slugFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	separator := "-"
+	if !param.IsNil() {
+		separator = param.String()
+	}
+	return pongo2.AsValue(slugify(in.String(), separator)), nil
+}
+
+func stripDiacriticsFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	return pongo2.AsValue(stripDiacritics(in.String())), nil
+}
+
+func nfcFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	return pongo2.AsValue(norm.NFC.String(in.String())), nil
+}
+
+func nfdFilter(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	if !in.IsString() {
+		return pongo2.AsValue(""), nil
+	}
+	return pongo2.AsValue(norm.NFD.String(in.String())), nil
+}
+
+func toASCII(s string) string {
+	var sb strings.Builder
+	input := []rune(norm.NFC.String(s))
+	for i, r := range input {
+		if r < unicode.MaxASCII {
+			sb.WriteRune(r)
+			continue
+		}
+		t, found := transliterationOverrides[r]
+		if !found {
+			t = unidecode.Unidecode(string(r))
+		}
+		// CJK transliterations end in a space to separate words, drop it before whitespace and at the end
+		if strings.HasSuffix(t, " ") && (i == len(input)-1 || unicode.IsSpace(input[i+1])) {
+			t = strings.TrimRight(t, " ")
+		}
+		sb.WriteString(t)
+	}
+	return sb.String()
+}
+
+func slugify(s string, separator string) string {
+	var sb strings.Builder
+	pendingSeparator := false
+	for _, r := range strings.ToLower(toASCII(s)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingSeparator && sb.Len() > 0 {
+				sb.WriteString(separator)
+			}
+			pendingSeparator = false
+			sb.WriteRune(r)
+		} else {
+			pendingSeparator = true
+		}
+	}
+	return sb.String()
+}
+
+func stripDiacritics(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return out
+}
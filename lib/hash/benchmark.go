@@ -0,0 +1,54 @@
+package hash
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+type BenchmarkResult struct {
+	Algorithm string
+	Duration  time.Duration
+	Bytes     int64
+}
+
+type BenchmarkList []BenchmarkResult
+
+// Benchmark hashes the same random data with every algorithm to compare their throughput on this machine
+func Benchmark(size int) BenchmarkList {
+	data := make([]byte, size)
+	rand.Read(data)
+	results := BenchmarkList{}
+	for _, name := range AlgorithmNames() {
+		h := Algorithms[name].New()
+		start := time.Now()
+		h.Write(data)
+		h.Sum(nil)
+		results = append(results, BenchmarkResult{Algorithm: name, Duration: time.Since(start), Bytes: int64(size)})
+	}
+	return results
+}
+
+func (b BenchmarkResult) Throughput() float64 {
+	return float64(b.Bytes) / b.Duration.Seconds() / 1e6
+}
+
+func (b BenchmarkList) ToTableData() pterm.TableData {
+	ret := [][]string{}
+	ret = append(ret, []string{"Algorithm", "Time", "MB/s", "Used By Default For"})
+	for _, result := range b {
+		usedFor := ""
+		switch result.Algorithm {
+		case DefaultAlgorithm:
+			usedFor = "fu hash, hash() template function"
+		case CompareAlgorithm:
+			usedFor = "comparing and deduplicating content"
+		case IntegrityAlgorithm:
+			usedFor = "integrity manifests"
+		}
+		ret = append(ret, []string{result.Algorithm, result.Duration.Round(time.Microsecond).String(), fmt.Sprintf("%.0f", result.Throughput()), usedFor})
+	}
+	return ret
+}
@@ -0,0 +1,57 @@
+package hash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+)
+
+// cacheUnavailable is set once the database couldn't be opened, usually because another fu process holds its lock,
+// so the remaining files are hashed without waiting on it again
+var cacheUnavailable int32
+
+// cacheKey is made of the device, inode, size, mtime and algorithm so any change to the file invalidates it
+func cacheKey(path string, stats os.FileInfo, algo string) string {
+	return fmt.Sprintf("%s:%d:%d:%s", fileID(path, stats), stats.Size(), stats.ModTime().UnixNano(), algo)
+}
+
+// CachedFile hashes a file, reusing the digest stored in the history database when the file hasn't changed
+func CachedFile(path string, algo Algorithm) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	stats, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	if atomic.LoadInt32(&cacheUnavailable) == 1 {
+		return File(abs, algo)
+	}
+	key := cacheKey(abs, stats, algo.Name)
+	cached, found, err := db.GetCachedHash(key)
+	if err != nil {
+		atomic.StoreInt32(&cacheUnavailable, 1)
+		return File(abs, algo)
+	}
+	if found {
+		return cached.Digest, nil
+	}
+	digest, err := File(abs, algo)
+	if err != nil {
+		return "", err
+	}
+	db.WriteCachedHash(key, abs, algo.Name, digest)
+	return digest, nil
+}
+
+// PruneCache removes cached digests for files that changed or no longer exist
+func PruneCache() (int, error) {
+	return db.PruneHashCache(func(key string, cached db.CachedHash) bool {
+		stats, err := os.Stat(cached.Path)
+		return err == nil && cacheKey(cached.Path, stats, cached.Algorithm) == key
+	})
+}
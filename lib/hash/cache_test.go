@@ -0,0 +1,69 @@
+package hash
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+)
+
+const helloSha256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestCachedFileHashesWhileDatabaseIsLocked(t *testing.T) {
+	dir := t.TempDir()
+	dbFile := filepath.Join(dir, "fu.db")
+	db.SetPath(dbFile)
+	defer db.CloseDB()
+	defer atomic.StoreInt32(&cacheUnavailable, 0)
+
+	// another fu process holding the database looks the same as a second handle in this one
+	holder, err := bolt.Open(dbFile, 0755, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Close()
+
+	path := filepath.Join(dir, "hello.txt")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		digest, err := CachedFile(path, Algorithms["sha256"])
+		if err != nil {
+			t.Fatalf("CachedFile() error = %v", err)
+		}
+		if digest != helloSha256 {
+			t.Errorf("CachedFile() = %q, want %q", digest, helloSha256)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CachedFile() waited %v on the locked database", elapsed)
+	}
+}
+
+func TestCachedFileReusesDigest(t *testing.T) {
+	dir := t.TempDir()
+	db.SetPath(filepath.Join(dir, "fu.db"))
+	defer db.CloseDB()
+
+	path := filepath.Join(dir, "hello.txt")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CachedFile(path, Algorithms["sha256"]); err != nil {
+		t.Fatalf("CachedFile() error = %v", err)
+	}
+	abs, _ := filepath.Abs(path)
+	stats, _ := os.Stat(abs)
+	cached, found, err := db.GetCachedHash(cacheKey(abs, stats, "sha256"))
+	if err != nil || !found || cached.Digest != helloSha256 {
+		t.Errorf("GetCachedHash() = %v, %v, %v, want the digest of hello", cached, found, err)
+	}
+}
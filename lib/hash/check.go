@@ -0,0 +1,161 @@
+package hash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	StatusOK      = "OK"
+	StatusFailed  = "FAILED"
+	StatusMissing = "MISSING"
+)
+
+type CheckEntry struct {
+	Path      string
+	Digest    string
+	Algorithm Algorithm
+	// other algorithms with the same digest length, such as xxh3 for a 16 character digest that was
+	// detected as xxh64, they are tried when Algorithm doesn't match
+	Alternatives []Algorithm
+}
+
+// ParseChecksumFile reads coreutils (hash  file) and BSD (ALGO (file) = hash) checksum lines,
+// the algorithm comes from the BSD tag or the digest length unless one is forced
+func ParseChecksumFile(path string, forced *Algorithm) ([]CheckEntry, []error) {
+	entries := []CheckEntry{}
+	problems := []error{}
+	f, err := os.Open(path)
+	if err != nil {
+		return entries, []error{err}
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry, err := parseChecksumLine(line, forced)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %d: %s", path, lineNumber, err.Error()))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		problems = append(problems, err)
+	}
+	return entries, problems
+}
+
+func parseChecksumLine(line string, forced *Algorithm) (CheckEntry, error) {
+	entry := CheckEntry{}
+	// coreutils prefixes lines whose file name contains a backslash or newline with a backslash
+	escaped := strings.HasPrefix(line, "\\")
+	if escaped {
+		line = line[1:]
+	}
+	if open := strings.Index(line, " ("); open > 0 && strings.Contains(line, ") = ") { // BSD style
+		closing := strings.LastIndex(line, ") = ")
+		entry.Path = line[open+2 : closing]
+		entry.Digest = strings.ToLower(line[closing+4:])
+		algo, err := algorithmForTag(line[:open])
+		if err != nil {
+			return entry, err
+		}
+		entry.Algorithm = algo
+	} else { // coreutils style, a * marks binary mode
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 || len(parts[1]) < 2 || (parts[1][0] != ' ' && parts[1][0] != '*') {
+			return entry, errors.New("improperly formatted checksum line")
+		}
+		entry.Digest = strings.ToLower(parts[0])
+		entry.Path = parts[1][1:]
+		algos := algorithmsForLength(len(entry.Digest))
+		if len(algos) == 0 && forced == nil {
+			return entry, fmt.Errorf("no hash algorithm has a %d character digest", len(entry.Digest))
+		}
+		if len(algos) > 0 {
+			entry.Algorithm, entry.Alternatives = algos[0], algos[1:]
+		}
+	}
+	if escaped {
+		entry.Path = strings.NewReplacer("\\\\", "\\", "\\n", "\n").Replace(entry.Path)
+	}
+	if forced != nil {
+		entry.Algorithm, entry.Alternatives = *forced, nil
+	}
+	return entry, nil
+}
+
+func algorithmForTag(tag string) (Algorithm, error) {
+	for _, algo := range Algorithms {
+		if strings.EqualFold(algo.Tag, tag) {
+			return algo, nil
+		}
+	}
+	return Algorithm{}, errors.New("unknown checksum tag " + tag)
+}
+
+// algorithmsForLength returns every algorithm with a digest of length hex characters in detection order
+func algorithmsForLength(length int) []Algorithm {
+	algos := []Algorithm{}
+	for _, name := range detectionOrder {
+		algo := Algorithms[name]
+		if algo.New().Size()*2 == length {
+			algos = append(algos, algo)
+		}
+	}
+	return algos
+}
+
+// Check hashes every entry concurrently and compares it to the expected digest, when the algorithm was
+// detected from the digest length every algorithm with that length is tried before the file fails
+func Check(entries []CheckEntry, useCache bool) ResultList {
+	results := make(ResultList, len(entries))
+	concurrently(len(entries), func(i int) {
+		entry := entries[i]
+		result := Result{Path: entry.Path, Algorithm: entry.Algorithm.Name}
+		digest, err := hashFile(entry.Path, entry.Algorithm, useCache)
+		for _, algo := range entry.Alternatives {
+			if err != nil || digest == entry.Digest {
+				break
+			}
+			if alternative, altErr := hashFile(entry.Path, algo, useCache); altErr == nil && alternative == entry.Digest {
+				digest, result.Algorithm = alternative, algo.Name
+			}
+		}
+		switch {
+		case os.IsNotExist(err):
+			result.Status = StatusMissing
+		case err != nil:
+			result.Status = StatusFailed
+			result.Error = err.Error()
+		case digest != entry.Digest:
+			result.Status = StatusFailed
+			result.Digest = digest
+		default:
+			result.Status = StatusOK
+			result.Digest = digest
+		}
+		results[i] = result
+	})
+	return results
+}
+
+// Count returns how many results have a status
+func (r ResultList) Count(status string) int {
+	count := 0
+	for _, result := range r {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
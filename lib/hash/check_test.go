@@ -0,0 +1,72 @@
+package hash
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSums(t *testing.T, dir string, lines ...string) string {
+	path := filepath.Join(dir, "SUMS")
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckDetectsAlgorithmsWithTheSameLength(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "hello.txt")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{}
+	for _, name := range []string{"xxh64", "xxh3", "sha256", "blake3", "sha512", "blake2b"} {
+		digest, err := File(path, Algorithms[name])
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, Result{Path: path, Algorithm: name, Digest: digest}.String(false))
+	}
+	entries, problems := ParseChecksumFile(writeSums(t, dir, lines...), nil)
+	if len(problems) > 0 {
+		t.Fatalf("ParseChecksumFile() problems = %v", problems)
+	}
+	want := []string{"xxh64", "xxh3", "sha256", "blake3", "sha512", "blake2b"}
+	for i, result := range Check(entries, false) {
+		if result.Status != StatusOK || result.Algorithm != want[i] {
+			t.Errorf("line %d: %s %s, want OK %s", i+1, result.Status, result.Algorithm, want[i])
+		}
+	}
+
+	// a digest that matches none of the algorithms with its length still fails
+	entries, _ = ParseChecksumFile(writeSums(t, dir, strings.Repeat("0", 16)+"  "+path), nil)
+	if results := Check(entries, false); results[0].Status != StatusFailed {
+		t.Errorf("Check() = %s, want %s", results[0].Status, StatusFailed)
+	}
+}
+
+func TestChecksumLinesEscapeFileNames(t *testing.T) {
+	tests := []struct {
+		path string
+		bsd  bool
+		want string
+	}{
+		{"plain.txt", false, "abcd  plain.txt"},
+		{`back\slash.txt`, false, `\abcd  back\\slash.txt`},
+		{"new\nline.txt", false, `\abcd  new\nline.txt`},
+		{`both\` + "\n.txt", true, `\CRC32 (both\\\n.txt) = abcd`},
+	}
+	for _, test := range tests {
+		line := Result{Path: test.path, Algorithm: "crc32", Digest: "abcd"}.String(test.bsd)
+		if line != test.want {
+			t.Errorf("String(%q) = %q, want %q", test.path, line, test.want)
+		}
+		crc32 := Algorithms["crc32"]
+		entry, err := parseChecksumLine(line, &crc32)
+		if err != nil || entry.Path != test.path {
+			t.Errorf("parseChecksumLine(%q) = %q, %v, want %q", line, entry.Path, err, test.path)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package hash
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file by device and inode so renamed or hard linked files share cache entries
+func fileID(path string, stats os.FileInfo) string {
+	if sys, ok := stats.Sys().(*syscall.Stat_t); ok {
+		return fmt.Sprintf("%d:%d", sys.Dev, sys.Ino)
+	}
+	return path
+}
@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package hash
+
+import (
+	"os"
+)
+
+// fileID identifies a file by its absolute path since os.FileInfo has no inode on Windows
+func fileID(path string, stats os.FileInfo) string {
+	return path
+}
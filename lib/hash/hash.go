@@ -0,0 +1,248 @@
+package hash
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	gohash "hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/pterm/pterm"
+	"github.com/zeebo/xxh3"
+	"golang.org/x/crypto/blake2b"
+	"lukechampine.com/blake3"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+type Algorithm struct {
+	Name string
+	Tag  string // name used by BSD style checksum lines
+	New  func() gohash.Hash
+}
+
+type Result struct {
+	Path      string `json:"path"`
+	Algorithm string `json:"algorithm"`
+	Digest    string `json:"digest,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ResultList []Result
+
+// Default algorithms used by each feature:
+//   - DefaultAlgorithm (sha256) for fu hash and the hash() template function, it matches sha256sum and is what
+//     published checksums use
+//   - CompareAlgorithm (xxh3) for detecting changed or duplicate content, it is the fastest and collisions
+//     only need to be unlikely, not impossible to forge
+//   - IntegrityAlgorithm (blake3) for manifests that guard against bit rot, it is cryptographic but still
+//     several times faster than sha256
+const (
+	DefaultAlgorithm   = "sha256"
+	CompareAlgorithm   = "xxh3"
+	IntegrityAlgorithm = "blake3"
+)
+
+var Algorithms = map[string]Algorithm{
+	"md5":     {Name: "md5", Tag: "MD5", New: md5.New},
+	"sha1":    {Name: "sha1", Tag: "SHA1", New: sha1.New},
+	"sha256":  {Name: "sha256", Tag: "SHA256", New: sha256.New},
+	"sha512":  {Name: "sha512", Tag: "SHA512", New: sha512.New},
+	"crc32":   {Name: "crc32", Tag: "CRC32", New: func() gohash.Hash { return crc32.NewIEEE() }},
+	"xxh64":   {Name: "xxh64", Tag: "XXH64", New: func() gohash.Hash { return xxhash.New() }},
+	"xxh3":    {Name: "xxh3", Tag: "XXH3", New: func() gohash.Hash { return xxh3.New() }},
+	"blake2b": {Name: "blake2b", Tag: "BLAKE2b", New: newBlake2b},
+	"blake3":  {Name: "blake3", Tag: "BLAKE3", New: func() gohash.Hash { return blake3.New(32, nil) }},
+}
+
+// detectionOrder decides which algorithm a checksum belongs to when several have the same digest length
+var detectionOrder = []string{"md5", "sha1", "sha256", "sha512", "crc32", "xxh64", "blake3", "blake2b", "xxh3"}
+
+func newBlake2b() gohash.Hash {
+	h, _ := blake2b.New512(nil) // only fails when given a key that is too long
+	return h
+}
+
+func AlgorithmNames() []string {
+	names := []string{}
+	for name := range Algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func GetAlgorithm(name string) (Algorithm, error) {
+	algo, found := Algorithms[strings.ToLower(name)]
+	if !found {
+		return Algorithm{}, errors.New("unknown hash algorithm " + name + ", use one of " + strings.Join(AlgorithmNames(), ", "))
+	}
+	return algo, nil
+}
+
+// File hashes the contents of a single file
+func File(path string, algo Algorithm) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := algo.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Partial hashes at most the first limit bytes of a file
+func Partial(path string, algo Algorithm, limit int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := algo.New()
+	if _, err := io.Copy(h, io.LimitReader(f, limit)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// PartialFiles hashes the start of files concurrently, results are in the same order as the paths
+func PartialFiles(paths []string, algo Algorithm, limit int64) ResultList {
+	results := make(ResultList, len(paths))
+	concurrently(len(paths), func(i int) {
+		result := Result{Path: paths[i], Algorithm: algo.Name}
+		digest, err := Partial(paths[i], algo, limit)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Digest = digest
+		}
+		results[i] = result
+	})
+	return results
+}
+
+// ExpandPaths matches globs and, when recursive, walks directories for the files inside them
+func ExpandPaths(globs []string, recursive bool) []string {
+	paths := []string{}
+	for _, g := range globs {
+		matches, err := util.Glob(g, "")
+		if err != nil {
+			pterm.Warning.Println(err.Error())
+			continue
+		}
+		if len(matches) == 0 && !strings.ContainsAny(g, "*?[") { // report missing files like any other read error
+			paths = append(paths, g)
+			continue
+		}
+		if len(matches) == 0 {
+			pterm.Warning.Printfln("%s does not match any existing files", g)
+		}
+		for _, match := range matches {
+			stats, err := os.Stat(match)
+			if err != nil {
+				pterm.Warning.Println(err.Error())
+				continue
+			}
+			if !stats.IsDir() {
+				paths = append(paths, match)
+				continue
+			}
+			if !recursive {
+				pterm.Warning.Printfln("%s is a directory, use --recursive to include its contents", match)
+				continue
+			}
+			filepath.Walk(match, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					pterm.Warning.Println(err.Error())
+					return nil
+				}
+				if info.Mode().IsRegular() {
+					paths = append(paths, path)
+				}
+				return nil
+			})
+		}
+	}
+	return paths
+}
+
+// Files hashes files concurrently, results are in the same order as the paths
+func Files(paths []string, algo Algorithm, useCache bool) ResultList {
+	results := make(ResultList, len(paths))
+	concurrently(len(paths), func(i int) {
+		result := Result{Path: paths[i], Algorithm: algo.Name}
+		digest, err := hashFile(paths[i], algo, useCache)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Digest = digest
+		}
+		results[i] = result
+	})
+	return results
+}
+
+func hashFile(path string, algo Algorithm, useCache bool) (string, error) {
+	if useCache {
+		return CachedFile(path, algo)
+	}
+	return File(path, algo)
+}
+
+// concurrently calls fn for 0 through count-1 spread over one worker per CPU
+func concurrently(count int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// String formats a result like coreutils (sha256sum) or, with bsd, like BSD's tagged output
+// String formats a coreutils or BSD style checksum line, like coreutils a file name containing a backslash or
+// newline is escaped and the line starts with a backslash
+func (r Result) String(bsd bool) string {
+	path, prefix := r.Path, ""
+	if strings.ContainsAny(path, "\\\n") {
+		path, prefix = strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(path), "\\"
+	}
+	if bsd {
+		return prefix + Algorithms[r.Algorithm].Tag + " (" + path + ") = " + r.Digest
+	}
+	return prefix + r.Digest + "  " + path
+}
+
+func (r ResultList) HasErrors() bool {
+	for _, result := range r {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
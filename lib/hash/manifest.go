@@ -0,0 +1,215 @@
+package hash
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ManifestName is where manifests are written inside the directory they describe
+const ManifestName = ".fu-manifest.json"
+
+const (
+	StatusChanged   = "CHANGED"
+	StatusCorrupted = "CORRUPTED"
+	StatusAdded     = "ADDED"
+	StatusRemoved   = "REMOVED"
+	StatusMoved     = "MOVED"
+)
+
+type Manifest struct {
+	Algorithm string          `json:"algorithm"`
+	Created   time.Time       `json:"created"`
+	Entries   []ManifestEntry `json:"entries"`
+}
+
+type ManifestEntry struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	Digest   string    `json:"digest"`
+}
+
+type ManifestChange struct {
+	Status string `json:"status"`
+	Path   string `json:"path"`
+	From   string `json:"from,omitempty"` // the old path of a moved file
+	Error  string `json:"error,omitempty"`
+}
+
+type ManifestChangeList []ManifestChange
+
+// manifestFile is a file found while walking a directory, path is relative to the directory and uses forward slashes
+type manifestFile struct {
+	path string
+	abs  string
+	info os.FileInfo
+}
+
+// CreateManifest hashes every file inside dir, the manifest itself is skipped
+func CreateManifest(dir string, manifestPath string, algo Algorithm) (Manifest, []error) {
+	manifest := Manifest{Algorithm: algo.Name, Created: time.Now(), Entries: []ManifestEntry{}}
+	files, problems := walkManifestDir(dir, manifestPath)
+	// manifests always read the files, a cached hash could hide the corruption they are meant to find
+	results := Files(manifestFilePaths(files), algo, false)
+	for i, f := range files {
+		if results[i].Error != "" {
+			problems = append(problems, errors.New(results[i].Error))
+			continue
+		}
+		manifest.Entries = append(manifest.Entries, ManifestEntry{Path: f.path, Size: f.info.Size(), Modified: f.info.ModTime(), Digest: results[i].Digest})
+	}
+	return manifest, problems
+}
+
+func ReadManifest(path string) (Manifest, error) {
+	manifest := Manifest{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, errors.New(path + " is not a valid manifest: " + err.Error())
+	}
+	return manifest, nil
+}
+
+func (m Manifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// VerifyManifest compares dir against the manifest. Files whose size or modified time changed are CHANGED,
+// files whose content changed while both stayed the same are CORRUPTED, and added files with the hash of a
+// removed file are MOVED. Unchanged files are returned with the OK status.
+func VerifyManifest(dir string, manifestPath string, manifest Manifest) (ManifestChangeList, error) {
+	algo, err := GetAlgorithm(manifest.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	files, problems := walkManifestDir(dir, manifestPath)
+	changes := ManifestChangeList{}
+	for _, problem := range problems {
+		changes = append(changes, ManifestChange{Status: StatusFailed, Error: problem.Error()})
+	}
+	current := map[string]manifestFile{}
+	for _, f := range files {
+		current[f.path] = f
+	}
+	results := Files(manifestFilePaths(files), algo, false)
+	digests := map[string]string{}
+	for i, f := range files {
+		if results[i].Error != "" {
+			changes = append(changes, ManifestChange{Status: StatusFailed, Path: f.path, Error: results[i].Error})
+			continue
+		}
+		digests[f.path] = results[i].Digest
+	}
+
+	listed := map[string]bool{}
+	removed := map[string][]string{} // digest to removed paths
+	for _, entry := range manifest.Entries {
+		listed[entry.Path] = true
+		f, exists := current[entry.Path]
+		if !exists {
+			removed[entry.Digest] = append(removed[entry.Digest], entry.Path)
+			continue
+		}
+		digest, hashed := digests[entry.Path]
+		if !hashed {
+			continue
+		}
+		sameStats := f.info.Size() == entry.Size && f.info.ModTime().Equal(entry.Modified)
+		switch {
+		case digest == entry.Digest:
+			changes = append(changes, ManifestChange{Status: StatusOK, Path: entry.Path})
+		case sameStats:
+			changes = append(changes, ManifestChange{Status: StatusCorrupted, Path: entry.Path})
+		default:
+			changes = append(changes, ManifestChange{Status: StatusChanged, Path: entry.Path})
+		}
+	}
+	for _, f := range files {
+		digest, hashed := digests[f.path]
+		if listed[f.path] || !hashed {
+			continue
+		}
+		if from := removed[digest]; len(from) > 0 {
+			changes = append(changes, ManifestChange{Status: StatusMoved, Path: f.path, From: from[0]})
+			removed[digest] = from[1:]
+			continue
+		}
+		changes = append(changes, ManifestChange{Status: StatusAdded, Path: f.path})
+	}
+	for _, paths := range removed {
+		for _, path := range paths {
+			changes = append(changes, ManifestChange{Status: StatusRemoved, Path: path})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+func (c ManifestChange) String() string {
+	switch {
+	case c.Error != "":
+		return c.Status + " " + c.Error
+	case c.From != "":
+		return c.Status + " " + c.From + " -> " + c.Path
+	}
+	return c.Status + " " + c.Path
+}
+
+func (c ManifestChangeList) Count(status string) int {
+	count := 0
+	for _, change := range c {
+		if change.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func walkManifestDir(dir string, manifestPath string) ([]manifestFile, []error) {
+	files := []manifestFile{}
+	problems := []error{}
+	skip, _ := filepath.Abs(manifestPath)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			problems = append(problems, err)
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if abs, _ := filepath.Abs(path); abs == skip {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			problems = append(problems, err)
+			return nil
+		}
+		files = append(files, manifestFile{path: filepath.ToSlash(rel), abs: path, info: info})
+		return nil
+	})
+	if err != nil {
+		problems = append(problems, err)
+	}
+	return files, problems
+}
+
+func manifestFilePaths(files []manifestFile) []string {
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.abs
+	}
+	return paths
+}
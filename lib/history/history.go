@@ -13,6 +13,7 @@ import (
 	"github.com/manifoldco/promptui"
 
 	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/operation"
 	"github.com/jhotmann/go-fileutils-cli/lib/util"
 	"github.com/pterm/pterm"
 )
@@ -155,7 +156,7 @@ func PrintBatch(batch db.Batch, returnPage int, countPerPage int) {
 		batch.Close()
 		break
 	case "u": // undo
-		batch.Undo()
+		operation.UndoBatch(batch)
 		batch.Close()
 		break
 	case "f": // favorite
@@ -167,7 +168,7 @@ func PrintBatch(batch db.Batch, returnPage int, countPerPage int) {
 		break
 	default: // undo selected operations
 		subset, _ := matchOperationsById(operations, result)
-		subset.Undo(batch.CommandType, batch.WorkingDir)
+		operation.Undo(subset, batch.CommandType, batch.WorkingDir)
 		batch.Close()
 	}
 }
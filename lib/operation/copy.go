@@ -0,0 +1,100 @@
+package operation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/1set/gut/yos"
+	"github.com/pterm/pterm"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+)
+
+// verifyAttempts is how many times a copy is made before a mismatch is reported as a failure
+const verifyAttempts = 3
+
+// copyPath copies a file or directory, with verify every copied file is re-read and compared to its input.
+// The output's hash is returned when a single file was verified.
+func copyPath(input string, output string, stats os.FileInfo, verify bool) (string, error) {
+	if !stats.IsDir() {
+		return copyFile(input, output, verify)
+	}
+	if err := yos.CopyDir(input, output); err != nil {
+		return "", err
+	}
+	if !verify {
+		return "", nil
+	}
+	return "", filepath.Walk(input, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(input, path)
+		if err != nil {
+			return err
+		}
+		_, err = verifyCopy(path, filepath.Join(output, rel))
+		return err
+	})
+}
+
+func copyFile(input string, output string, verify bool) (string, error) {
+	if err := yos.CopyFile(input, output); err != nil {
+		return "", err
+	}
+	if !verify {
+		return "", nil
+	}
+	return verifyCopy(input, output)
+}
+
+// verifyCopy compares the hashes of an input and its copy, copying again when they don't match
+func verifyCopy(input string, output string) (string, error) {
+	algo := hash.Algorithms[hash.CompareAlgorithm]
+	expected, err := hash.File(input, algo)
+	if err != nil {
+		return "", err
+	}
+	for attempt := 1; ; attempt++ {
+		if err := flushCopy(output); err != nil {
+			return "", err
+		}
+		actual, err := hash.File(output, algo)
+		if err != nil {
+			return "", err
+		}
+		if actual == expected {
+			return actual, nil
+		}
+		if attempt == verifyAttempts {
+			return "", fmt.Errorf("%s does not match %s after %d attempts", output, input, verifyAttempts)
+		}
+		pterm.Warning.Printfln("%s does not match %s, copying again", output, input)
+		if err := yos.CopyFile(input, output); err != nil {
+			return "", err
+		}
+	}
+}
+
+// flushCopy writes the copy to the device and, where the system allows it, drops it from the page cache
+// so hashing it reads what the device stored instead of what is still in memory
+func flushCopy(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil { // read only copies can still be synced on most systems
+		if f, err = os.Open(path); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return dropCachedPages(f)
+}
+
+func isCrossDevice(err error) bool {
+	var linkErr *os.LinkError
+	return errors.As(err, &linkErr) && linkErr.Err == errNotSameDevice
+}
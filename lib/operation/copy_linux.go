@@ -0,0 +1,19 @@
+//go:build linux
+// +build linux
+
+package operation
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// errNotSameDevice is returned when renaming between file systems
+var errNotSameDevice = syscall.EXDEV
+
+// dropCachedPages evicts a synced file from the page cache so it is read back from the device
+func dropCachedPages(f *os.File) error {
+	return unix.Fadvise(int(f.Fd()), 0, 0, unix.FADV_DONTNEED)
+}
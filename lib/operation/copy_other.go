@@ -0,0 +1,18 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package operation
+
+import (
+	"os"
+	"syscall"
+)
+
+// errNotSameDevice is returned when renaming between file systems
+var errNotSameDevice = syscall.EXDEV
+
+// dropCachedPages does nothing, there is no portable way to evict a single file from the cache so verified
+// copies may be read back from memory
+func dropCachedPages(f *os.File) error {
+	return nil
+}
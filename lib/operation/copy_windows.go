@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package operation
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// errNotSameDevice is returned when renaming between volumes
+var errNotSameDevice = windows.ERROR_NOT_SAME_DEVICE
+
+// dropCachedPages does nothing, Windows has no way to evict a single file from its cache so verified
+// copies may be read back from memory
+func dropCachedPages(f *os.File) error {
+	return nil
+}
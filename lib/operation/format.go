@@ -0,0 +1,61 @@
+package operation
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/tags"
+	"github.com/jhotmann/go-fileutils-cli/lib/util"
+)
+
+// Recurse adds everything inside directories right after the directory
+func (o OperationList) Recurse() OperationList {
+	ret := OperationList{}
+	for _, op := range o {
+		ret = append(ret, op)
+		if !op.Stats.IsDir() {
+			continue
+		}
+		filepath.Walk(op.Input.Abs, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				pterm.Warning.Println(err.Error())
+				return nil
+			}
+			if path == op.Input.Abs {
+				return nil
+			}
+			child := op
+			child.Input = util.GetPathObj(path)
+			child.Stats = info
+			ret = append(ret, child)
+			return nil
+		})
+	}
+	return ret
+}
+
+// Format renders each operation's template as a line of output, {{ i }} is replaced with the 1-based line number
+func (o OperationList) Format(opts options.CommonOptions) ([]string, error) {
+	lines := []string{}
+	for _, op := range o {
+		out, err := op.OutputTemplate.Execute(op.Context())
+		if err != nil {
+			return lines, err
+		}
+		if strings.Contains(out, tags.SkipMarker) {
+			if opts.Verbose {
+				pterm.Info.Printfln("Skipping %s because the template called skip", op.Input.Rel)
+			}
+			continue
+		}
+		out = strings.ReplaceAll(out, "--REPLACEME--", "")
+		out = strings.ReplaceAll(out, "--FILEINDEXHERE--", strconv.Itoa(len(lines)+1))
+		lines = append(lines, out)
+	}
+	return lines, nil
+}
@@ -9,11 +9,13 @@ import (
 	"strings"
 	"time"
 
-	"github.com/1set/gut/yos"
 	"github.com/flosch/pongo2/v4"
 	"github.com/jhotmann/go-fileutils-cli/lib/db"
-	_ "github.com/jhotmann/go-fileutils-cli/lib/filters"
+	"github.com/jhotmann/go-fileutils-cli/lib/filters"
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
 	"github.com/jhotmann/go-fileutils-cli/lib/options"
+	"github.com/jhotmann/go-fileutils-cli/lib/snippets"
+	"github.com/jhotmann/go-fileutils-cli/lib/tags"
 	"github.com/jhotmann/go-fileutils-cli/lib/util"
 	"github.com/manifoldco/promptui"
 	"github.com/pterm/pterm"
@@ -28,14 +30,17 @@ type Operation struct {
 	HasConflict    bool
 	Index          int
 	ConflictCount  int
+	Location       *time.Location // the time zone dates are rendered in
 }
 
 type OperationList []Operation
 
-func FilesToOperationsList(opType string, files []string, outputTemplate *pongo2.Template) OperationList {
+func FilesToOperationsList(opType string, files []string, opts options.CommonOptions, outputTemplates ...*pongo2.Template) OperationList {
 	operations := []Operation{}
+	loc, _ := util.LoadTimeZone(opts.TimeZone) // commands report invalid time zones before getting here
+	filters.SetParseLocation(loc)
 	for _, f := range files {
-		matches, err := filepath.Glob(f)
+		matches, err := util.Glob(f, opts.Normalize)
 		if err != nil {
 			panic(err)
 		}
@@ -43,46 +48,43 @@ func FilesToOperationsList(opType string, files []string, outputTemplate *pongo2
 			pterm.Warning.Printfln("%s does not match any existing files", f)
 		}
 		for _, match := range matches {
-			var op Operation
-			op.Type = opType
-			op.Input = util.GetPathObj(match)
-			op.OutputTemplate = outputTemplate
 			stats, err := os.Stat(match)
 			if err != nil {
 				pterm.Warning.Println(err.Error())
 				continue
 			}
-			op.Stats = stats
-			operations = append(operations, op)
+			for _, outputTemplate := range outputTemplates {
+				var op Operation
+				op.Type = opType
+				op.Input = util.GetPathObj(match)
+				op.OutputTemplate = outputTemplate
+				op.Stats = stats
+				op.Location = loc
+				operations = append(operations, op)
+			}
 		}
 	}
 	return operations
 }
 
 func (o OperationList) RemoveDirectories() OperationList {
-	directoryIndex := []int{}
-	for i, op := range o {
-		if op.Stats.IsDir() {
-			directoryIndex = append(directoryIndex, i)
-		}
-	}
-	for i, dirIndex := range directoryIndex { // I don't know why this works, but it does
-		if dirIndex == len(o)-1 {
-			return o[:dirIndex-i]
-		} else {
-			return append(o[:dirIndex-i], o[dirIndex+1-i])
+	ret := OperationList{}
+	for _, op := range o {
+		if !op.Stats.IsDir() {
+			ret = append(ret, op)
 		}
 	}
-	return o
+	return ret
 }
 
 func (o OperationList) RemoveDuplicateInputs() OperationList {
 	ret := OperationList{}
-	paths := []string{}
+	seen := map[string]bool{}
 	for _, op := range o {
-		abs := op.Input.Abs
-		if util.IndexOf(abs, paths) == -1 {
-			paths = append(paths, abs)
+		// the same input may appear once per output template
+		key := fmt.Sprintf("%s|%p", op.Input.Abs, op.OutputTemplate)
+		if !seen[key] {
+			seen[key] = true
 			ret = append(ret, op)
 		}
 	}
@@ -90,49 +92,110 @@ func (o OperationList) RemoveDuplicateInputs() OperationList {
 }
 
 func (o OperationList) Sort(sortOption string) OperationList {
+	// stable sorts keep the outputs of a single input in the order they were given
 	switch sortOption {
 	case "alphabet":
-		sort.Slice(o, func(i, j int) bool { return o[i].Input.Abs < o[j].Input.Abs })
+		sort.SliceStable(o, func(i, j int) bool { return o[i].Input.Abs < o[j].Input.Abs })
 	case "reverse-alphabet":
-		sort.Slice(o, func(i, j int) bool { return o[j].Input.Abs < o[i].Input.Abs })
+		sort.SliceStable(o, func(i, j int) bool { return o[j].Input.Abs < o[i].Input.Abs })
 	case "date":
-		sort.Slice(o, func(i, j int) bool { return o[i].Stats.ModTime().Before(o[j].Stats.ModTime()) })
+		sort.SliceStable(o, func(i, j int) bool { return o[i].Stats.ModTime().Before(o[j].Stats.ModTime()) })
 	case "reverse-date":
-		sort.Slice(o, func(i, j int) bool { return o[i].Stats.ModTime().After(o[j].Stats.ModTime()) })
+		sort.SliceStable(o, func(i, j int) bool { return o[i].Stats.ModTime().After(o[j].Stats.ModTime()) })
 	case "size":
-		sort.Slice(o, func(i, j int) bool { return o[i].Stats.Size() > o[j].Stats.Size() })
+		sort.SliceStable(o, func(i, j int) bool { return o[i].Stats.Size() > o[j].Stats.Size() })
 	case "reverse-size":
-		sort.Slice(o, func(i, j int) bool { return o[j].Stats.Size() > o[i].Stats.Size() })
+		sort.SliceStable(o, func(i, j int) bool { return o[j].Stats.Size() > o[i].Stats.Size() })
 	}
 	return o
 }
 
-func (o OperationList) RenderTemplates() OperationList {
+func (op Operation) Context() pongo2.Context {
+	loc := op.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return pongo2.Context{
+		"i":           "--FILEINDEXHERE--",
+		"f":           op.Input.Name,
+		"abs":         op.Input.Abs,
+		"rel":         op.Input.Rel,
+		"ext":         op.Input.Ext,
+		"p":           filepath.Dir(op.Input.Dir),
+		"isDirectory": fmt.Sprintf("%t", op.Stats.IsDir()),
+		"date": map[string]time.Time{
+			"now":      time.Now().In(loc),
+			"modified": op.Stats.ModTime().In(loc),
+		},
+		"size": op.Stats.Size(),
+		"use":  snippets.Use,
+		"hash": func(algorithm string) (string, error) { // only hashed when a template uses it
+			algo, err := hash.GetAlgorithm(algorithm)
+			if err != nil {
+				return "", err
+			}
+			return hash.CachedFile(op.Input.Abs, algo)
+		},
+	}
+}
+
+func (o OperationList) Where(expression string, opts options.CommonOptions) (OperationList, error) {
+	if strings.TrimSpace(expression) == "" {
+		return o, nil
+	}
+	// wrap the expression in an if tag so pongo2 does the evaluating
+	whereTemplate, err := snippets.FromString("{% if " + expression + " %}true{% endif %}")
+	if err != nil {
+		return o, err
+	}
 	ret := OperationList{}
+	filtered := []string{}
 	for _, op := range o {
-		context := pongo2.Context{
-			"i":           "--FILEINDEXHERE--",
-			"f":           op.Input.Name,
-			"abs":         op.Input.Abs,
-			"rel":         op.Input.Rel,
-			"ext":         op.Input.Ext,
-			"p":           filepath.Dir(op.Input.Dir),
-			"isDirectory": fmt.Sprintf("%t", op.Stats.IsDir()),
-			"date": map[string]time.Time{
-				"now":      time.Now(),
-				"modified": op.Stats.ModTime(),
-			},
-			"size": op.Stats.Size(),
+		out, err := whereTemplate.Execute(op.Context())
+		if err != nil {
+			return o, err
+		}
+		if out == "true" {
+			ret = append(ret, op)
+			continue
 		}
-		out, err := op.OutputTemplate.Execute(context)
+		if util.IndexOf(op.Input.Abs, filtered) == -1 {
+			filtered = append(filtered, op.Input.Abs)
+		}
+		if opts.Verbose {
+			pterm.Info.Printfln("Skipping %s because it does not match --where", op.Input.Rel)
+		}
+	}
+	if opts.Simulate {
+		pterm.Info.Printfln("%d input(s) filtered out by --where", len(filtered))
+	}
+	return ret, nil
+}
+
+func (o OperationList) RenderTemplates(opts options.CommonOptions) (OperationList, error) {
+	ret := OperationList{}
+	for _, op := range o {
+		out, err := op.OutputTemplate.Execute(op.Context())
 		if err != nil {
-			panic(err)
+			return o, err
 		}
 		out = strings.ReplaceAll(out, "--REPLACEME--", "")
+		if strings.Contains(out, tags.SkipMarker) { // template asked for this file to be skipped
+			if opts.Verbose {
+				pterm.Info.Printfln("Skipping %s because the template called skip", op.Input.Rel)
+			}
+			continue
+		}
+		if strings.TrimSpace(out) == "" { // an empty output would resolve to the working directory
+			if opts.Verbose {
+				pterm.Info.Printfln("Skipping %s because the output is empty", op.Input.Rel)
+			}
+			continue
+		}
 		op.Output = util.GetPathObj(out)
 		ret = append(ret, op)
 	}
-	return ret
+	return ret, nil
 }
 
 func (o OperationList) PopulateBlankExtensions() OperationList {
@@ -155,24 +218,54 @@ func (o OperationList) NoMove() OperationList {
 	return ret
 }
 
-func (o OperationList) FindConflicts() OperationList {
+func (o OperationList) Portable(platform string, opts options.CommonOptions) OperationList {
+	ret := OperationList{}
+	for _, op := range o {
+		cleaned, changes, err := util.SanitizePath(op.Output.Abs, platform)
+		if err != nil {
+			pterm.Error.Printfln("Skipping %s: %s", op.Input.Rel, err.Error())
+			continue
+		}
+		if opts.Simulate || opts.Verbose {
+			for _, change := range changes {
+				pterm.Info.Printfln("%s: %s", op.Input.Rel, change)
+			}
+		}
+		op.Output = util.GetPathObj(cleaned)
+		ret = append(ret, op)
+	}
+	return ret
+}
+
+func (o OperationList) Normalize(form string) OperationList {
+	ret := OperationList{}
+	for _, op := range o {
+		op.Output = util.GetPathObj(util.NormalizePath(op.Output.Abs, form))
+		ret = append(ret, op)
+	}
+	return ret
+}
+
+func (o OperationList) FindConflicts(opts options.CommonOptions) OperationList {
 	ret := OperationList{}
 	counts := map[string]int{}
 	indicies := map[string]int{}
 	for _, op := range o {
-		value := counts[op.Output.Abs] // If key doesn't exist, value will be zero
-		counts[op.Output.Abs] = value + 1
+		key := util.Normalize(op.Output.Abs, opts.Normalize)
+		value := counts[key] // If key doesn't exist, value will be zero
+		counts[key] = value + 1
 	}
 	for _, op := range o {
-		count := counts[op.Output.Abs]
+		key := util.Normalize(op.Output.Abs, opts.Normalize)
+		count := counts[key]
 		op.ConflictCount = count
 		if count == 1 {
 			op.Index = 1
 			op.HasConflict = false
 		} else {
 			op.HasConflict = true
-			index := indicies[op.Output.Abs] + 1
-			indicies[op.Output.Abs] = index
+			index := indicies[key] + 1
+			indicies[key] = index
 			op.Index = index
 		}
 		ret = append(ret, op)
@@ -199,6 +292,10 @@ func (o OperationList) AddIndex() OperationList {
 }
 
 func (o OperationList) Run(command []string, opts options.CommonOptions) {
+	if len(o) == 0 {
+		pterm.Warning.Println("No operations to run")
+		return
+	}
 	var batch db.Batch
 	if !opts.Simulate {
 		batch = db.NewBatch(o[0].Type, command, util.GetWorkingDir())
@@ -206,11 +303,14 @@ func (o OperationList) Run(command []string, opts options.CommonOptions) {
 	defer batch.Close()
 	for _, op := range o {
 		var err error
+		var digest string
 		if opts.Simulate {
 			pterm.Info.Printfln("%s → %s", op.Input.Rel, op.Output.Rel)
 			continue
 		}
-		if op.Input.Abs == op.Output.Abs { // no change
+		// only a move can change the normalization of a name, anything else would create a lookalike
+		renormalize := op.Type == "move" && op.Input.Abs != op.Output.Abs
+		if util.Normalize(op.Input.Abs, opts.Normalize) == util.Normalize(op.Output.Abs, opts.Normalize) && !renormalize { // no change
 			if opts.Verbose {
 				pterm.Info.Printfln("Skipping %s because it did not change", op.Input.Rel)
 			}
@@ -224,14 +324,13 @@ func (o OperationList) Run(command []string, opts options.CommonOptions) {
 			}
 		}
 		if opts.Force { // Do the operation with reckless abadon
-			err = op.runOperation()
+			digest, err = op.runOperation(opts.Verify)
 		} else {
-			_, err := os.Stat(op.Output.Abs)
-			if os.IsNotExist(err) { // File/Dir doesn't exist so we can proceed
-				err = op.runOperation()
+			if !util.PathExists(op.Output.Abs, opts.Normalize) { // File/Dir doesn't exist so we can proceed
+				digest, err = op.runOperation(opts.Verify)
 			} else { // File/Dir already exists, check with user what to do
-				if strings.ToLower(op.Input.Abs) == strings.ToLower(op.Output.Abs) && op.Type == "move" { // rename with case change, allow it
-					op.runOperation()
+				if strings.ToLower(util.Normalize(op.Input.Abs, opts.Normalize)) == strings.ToLower(util.Normalize(op.Output.Abs, opts.Normalize)) && op.Type == "move" { // rename with case or normalization change, allow it
+					digest, err = op.runOperation(opts.Verify)
 				} else { // Prompt for user input
 					fmt.Println()
 					pterm.Warning.Printfln("What should happen to %s, %s already exists", op.Input.Rel, op.Output.Rel)
@@ -242,19 +341,19 @@ func (o OperationList) Run(command []string, opts options.CommonOptions) {
 					index, _, _ := prompt.Run()
 					switch index {
 					case 0:
-						op.runOperation()
+						digest, err = op.runOperation(opts.Verify)
 						break
 					case 1:
 						prompt2 := promptui.Prompt{
 							Label:   "New File Name",
 							Default: op.Output.Name,
 						}
-						val, err := prompt2.Run()
-						if err != nil {
-							panic(err)
+						val, promptErr := prompt2.Run()
+						if promptErr != nil {
+							panic(promptErr)
 						}
 						op.Output = op.Output.UpdateName(val)
-						op.runOperation()
+						digest, err = op.runOperation(opts.Verify)
 						break
 					case 2:
 						if opts.Verbose {
@@ -269,24 +368,32 @@ func (o OperationList) Run(command []string, opts options.CommonOptions) {
 			pterm.Error.Println(err.Error())
 			continue
 		}
-		db.WriteOperation(batch.Id, op.Input.Abs, op.Output.Abs)
+		algorithm := ""
+		if opts.Verify {
+			algorithm = hash.CompareAlgorithm
+		}
+		db.WriteOperation(batch.Id, op.Input.Abs, op.Output.Abs, algorithm, digest)
 		if opts.Verbose {
 			pterm.Success.Printfln("%s → %s", op.Input.Rel, op.Output.Rel)
 		}
 	}
 }
 
-func (o Operation) runOperation() error {
+// runOperation returns the output's hash when a single file was copied with verify
+func (o Operation) runOperation(verify bool) (string, error) {
 	var err error
+	var digest string
 	switch o.Type {
 	case "move":
 		err = os.Rename(o.Input.Abs, o.Output.Abs)
-	case "copy":
-		if o.Stats.IsDir() {
-			yos.CopyDir(o.Input.Abs, o.Output.Abs)
-		} else {
-			yos.CopyFile(o.Input.Abs, o.Output.Abs)
+		if isCrossDevice(err) { // rename can't move between file systems, copy then remove the input instead
+			digest, err = copyPath(o.Input.Abs, o.Output.Abs, o.Stats, verify)
+			if err == nil {
+				err = os.RemoveAll(o.Input.Abs)
+			}
 		}
+	case "copy":
+		digest, err = copyPath(o.Input.Abs, o.Output.Abs, o.Stats, verify)
 	case "link-soft":
 		err = os.Symlink(o.Input.Abs, o.Output.Abs)
 	case "link-hard":
@@ -294,5 +401,5 @@ func (o Operation) runOperation() error {
 	default:
 		err = errors.New(o.Type + " not implemented")
 	}
-	return err
+	return digest, err
 }
@@ -0,0 +1,138 @@
+package operation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/1set/gut/yos"
+	"github.com/pterm/pterm"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+	"github.com/jhotmann/go-fileutils-cli/lib/trash"
+)
+
+// UndoBatch undoes every operation in a batch, the batch is only saved as undone once all of them succeeded
+func UndoBatch(batch db.Batch) error {
+	if batch.Undone {
+		pterm.Warning.Println("Batch already undone")
+		return errors.New("Batch already undone")
+	}
+	operations, err := db.GetOperationsForBatch(batch.Id)
+	if err != nil {
+		return err
+	}
+	if err = Undo(operations, batch.CommandType, batch.WorkingDir); err != nil {
+		return err
+	}
+	return batch.MarkUndone()
+}
+
+// Undo reverts operations of a batch, each one is saved as undone right after it succeeds so a failed undo can be run again
+func Undo(ops db.OperationList, commandType string, cwd string) error {
+	var err error
+	if commandType == "mkdir" || commandType == "touch" { // remove created files before the directories they're in
+		ops = ops.Reverse()
+	}
+	for _, op := range ops {
+		input := strings.Replace(op.Input, cwd, "", 1)
+		output := strings.Replace(op.Output, cwd, "", 1)
+		if op.Undone {
+			pterm.Info.Printfln("%s already undone", output)
+			continue
+		}
+		if commandType == "mkdir" || commandType == "touch" {
+			err = os.Remove(op.Output) // never remove anything that was added to a created directory
+			if err != nil && !os.IsNotExist(err) {
+				pterm.Warning.Printfln("Kept %s, %s", output, err.Error())
+				continue
+			}
+			pterm.Success.Printfln("Removed %s", output)
+		} else if commandType == "trash" {
+			err = trash.Restore(op.Output, op.Input)
+			if err != nil {
+				pterm.Warning.Printfln("Could not restore %s from the trash", input)
+				return err
+			}
+			pterm.Success.Printfln("Restored %s", input)
+		} else if commandType == "dedupe" {
+			err = restoreDuplicate(op)
+			if err != nil {
+				pterm.Warning.Printfln("Could not restore %s from %s", output, input)
+				return err
+			}
+			pterm.Success.Printfln("Restored %s", output)
+		} else if commandType == "move" {
+			err = moveBack(op)
+			if err != nil {
+				pterm.Warning.Printfln("Could not move %s back to %s", output, input)
+				return err
+			}
+			pterm.Success.Printfln("%s → %s", output, input)
+		} else {
+			err = os.RemoveAll(op.Output)
+			if err != nil {
+				pterm.Warning.Printfln("Could not delete %s", op.Output)
+				return err
+			}
+			pterm.Success.Printfln("Deleted %s", output)
+		}
+		if err = op.MarkUndone(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveBack renames an output back to its input, between file systems it is copied back (and verified again when
+// the move was) before the output is removed
+func moveBack(op db.Operation) error {
+	err := os.Rename(op.Output, op.Input)
+	if !isCrossDevice(err) {
+		return err
+	}
+	stats, err := os.Lstat(op.Output)
+	if err != nil {
+		return err
+	}
+	if _, err = copyPath(op.Output, op.Input, stats, op.Algorithm != ""); err != nil {
+		return err
+	}
+	return os.RemoveAll(op.Output)
+}
+
+// restoreDuplicate replaces whatever is at the output with a copy of the kept input, as long as the input still has
+// the contents the duplicate was removed for
+func restoreDuplicate(op db.Operation) error {
+	if op.Hash != "" {
+		algo, err := hash.GetAlgorithm(op.Algorithm)
+		if err != nil {
+			return err
+		}
+		digest, err := hash.File(op.Input, algo)
+		if err != nil {
+			return err
+		}
+		if digest != op.Hash {
+			return fmt.Errorf("%s changed since %s was deduplicated", op.Input, op.Output)
+		}
+	}
+	if err := os.Remove(op.Output); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yos.CopyFile(op.Input, op.Output); err != nil {
+		return err
+	}
+	if op.Mode != 0 {
+		if err := os.Chmod(op.Output, op.Mode); err != nil {
+			return err
+		}
+	}
+	if op.Modified != nil {
+		return os.Chtimes(op.Output, time.Now(), *op.Modified)
+	}
+	return nil
+}
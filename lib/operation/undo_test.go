@@ -0,0 +1,63 @@
+package operation
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/db"
+	"github.com/jhotmann/go-fileutils-cli/lib/hash"
+)
+
+func dedupeOperation(t *testing.T, dir string) db.Operation {
+	kept := filepath.Join(dir, "kept.txt")
+	if err := ioutil.WriteFile(kept, []byte("same"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := hash.File(kept, hash.Algorithms[hash.CompareAlgorithm])
+	if err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return db.Operation{
+		Input:     kept,
+		Output:    filepath.Join(dir, "duplicate.txt"),
+		Hash:      digest,
+		Algorithm: hash.CompareAlgorithm,
+		Mode:      0600,
+		Modified:  &modified,
+	}
+}
+
+func TestRestoreDuplicate(t *testing.T) {
+	op := dedupeOperation(t, t.TempDir())
+	if err := restoreDuplicate(op); err != nil {
+		t.Fatalf("restoreDuplicate() error = %v", err)
+	}
+	contents, err := ioutil.ReadFile(op.Output)
+	if err != nil || string(contents) != "same" {
+		t.Errorf("restored %q, %v, want %q", contents, err, "same")
+	}
+	stats, err := os.Stat(op.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Mode().Perm() != op.Mode || !stats.ModTime().Equal(*op.Modified) {
+		t.Errorf("restored mode %v modified %v, want %v and %v", stats.Mode().Perm(), stats.ModTime(), op.Mode, *op.Modified)
+	}
+}
+
+func TestRestoreDuplicateRefusesChangedInput(t *testing.T) {
+	op := dedupeOperation(t, t.TempDir())
+	if err := ioutil.WriteFile(op.Input, []byte("edited after dedupe"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := restoreDuplicate(op); err == nil {
+		t.Fatal("restoreDuplicate() restored a duplicate from an input that changed")
+	}
+	if _, err := os.Lstat(op.Output); !os.IsNotExist(err) {
+		t.Errorf("restoreDuplicate() created %s, err = %v", op.Output, err)
+	}
+}
@@ -0,0 +1,92 @@
+package operation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/jhotmann/go-fileutils-cli/lib/tags"
+)
+
+// functionUsage describes the context functions since their values can't be shown
+var functionUsage = map[string]string{
+	"use":  `function, use("snippet name") renders a saved template`,
+	"hash": `function, hash("algorithm") hashes the file, like hash("sha256")`,
+}
+
+// Variables is the template context with values that can be printed, times are formatted as RFC 3339
+func (op Operation) Variables() map[string]interface{} {
+	vars := map[string]interface{}{}
+	for key, value := range op.Context() {
+		vars[key] = displayValue(key, value)
+	}
+	vars["i"] = "index, only filled in when several outputs have the same name"
+	return vars
+}
+
+func displayValue(key string, value interface{}) interface{} {
+	if usage, isFunction := functionUsage[key]; isFunction {
+		return usage
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case map[string]time.Time:
+		ret := map[string]interface{}{}
+		for k, t := range v {
+			ret[k] = displayValue(k, t)
+		}
+		return ret
+	}
+	if reflect.ValueOf(value).Kind() == reflect.Func {
+		return "function"
+	}
+	return value
+}
+
+// VariablesTree lists the variables, and the variables inside namespaces like date, in alphabetical order
+func VariablesTree(vars map[string]interface{}) pterm.LeveledList {
+	list := pterm.LeveledList{}
+	for _, key := range sortedKeys(vars) {
+		if namespace, isNamespace := vars[key].(map[string]interface{}); isNamespace {
+			list = append(list, pterm.LeveledListItem{Level: 0, Text: key})
+			for _, child := range sortedKeys(namespace) {
+				list = append(list, pterm.LeveledListItem{Level: 1, Text: fmt.Sprintf("%s: %v", child, namespace[child])})
+			}
+			continue
+		}
+		list = append(list, pterm.LeveledListItem{Level: 0, Text: fmt.Sprintf("%s: %v", key, vars[key])})
+	}
+	return list
+}
+
+// TestTemplate renders the operation's template the way mv, cp and ln would before conflicts are indexed,
+// skipped explains why RenderTemplates would leave the file out
+func (op Operation) TestTemplate() (output string, skipped string, err error) {
+	output, err = op.OutputTemplate.Execute(op.Context())
+	if err != nil {
+		return "", "", err
+	}
+	output = strings.ReplaceAll(output, "--REPLACEME--", "")
+	output = strings.ReplaceAll(output, "--FILEINDEXHERE--", "")
+	if strings.Contains(output, tags.SkipMarker) {
+		return output, "the template called skip", nil
+	}
+	if strings.TrimSpace(output) == "" {
+		return output, "the output is empty", nil
+	}
+	return output, "", nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
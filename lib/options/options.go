@@ -1,6 +1,9 @@
 package options
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/jhotmann/go-fileutils-cli/lib/util"
 	"github.com/spf13/cobra"
 )
@@ -17,7 +20,37 @@ var (
 	NoExt             = false
 	NoMkdir           = false
 	Soft              = false
+	To                = []string{}
+	Where             = ""
+	Portable          = ""
+	Normalize         = ""
+	TimeZone          = ""
+	Verify            = false
+	Algorithm         = "sha256"
+	Benchmark         = false
+	Recursive         = false
+	BSD               = false
+	JSON              = false
+	Check             = false
+	Quiet             = false
+	NoCache           = false
+	Manifest          = ""
+	Strategy          = "oldest"
+	Keep              = ""
+	Action            = ""
+	Permanent         = false
+	NamesFile         = ""
+	Range             = ""
+	Vars              = []string{}
+	Format            = "{{ rel }}"
+	Null              = false
+	Template          = ""
 	AllowedSortValues = []string{"none", "alphabet", "reverse-alphabet", "date", "reverse-date", "size", "reverse-size"}
+	AllowedPortable   = []string{"", "posix", "windows", "macos"}
+	AllowedNormalize  = []string{"", "nfc", "nfd"}
+	AllowedStrategies = []string{"oldest", "newest", "shortest"}
+	AllowedActions    = []string{"", "delete", "hardlink", "symlink"}
+	hashAliases       = map[string]bool{"md5": true, "sha1": true, "sha256": true, "sha512": true, "crc32": true, "xxh64": true, "xxh3": true, "blake2b": true, "blake3": true}
 )
 
 type CommonOptions struct {
@@ -29,6 +62,12 @@ type CommonOptions struct {
 	NoIndex           bool
 	NoExt             bool
 	NoMkdir           bool
+	To                []string
+	Where             string
+	Portable          string
+	Normalize         string
+	TimeZone          string
+	Verify            bool
 }
 
 type MoveOptions struct {
@@ -41,6 +80,34 @@ type LinkOptions struct {
 	Soft bool
 }
 
+type HashOptions struct {
+	Algorithm       string
+	AlgorithmForced bool
+	Recursive       bool
+	BSD             bool
+	JSON            bool
+	Check           bool
+	Quiet           bool
+	NoCache         bool
+	Benchmark       bool
+}
+
+// ValidatePortable rejects unknown --portable platforms instead of silently turning cleaning off
+func ValidatePortable(platform string) error {
+	if util.IndexOf(platform, AllowedPortable) == -1 {
+		return errors.New("'" + platform + "' must be one of " + strings.Join(AllowedPortable[1:], ", "))
+	}
+	return nil
+}
+
+// ValidateNormalize rejects unknown --normalize forms instead of silently turning normalization off
+func ValidateNormalize(form string) error {
+	if util.IndexOf(form, AllowedNormalize) == -1 {
+		return errors.New("'" + form + "' must be one of " + strings.Join(AllowedNormalize[1:], ", "))
+	}
+	return nil
+}
+
 func GetCommonOptions(cmd *cobra.Command) CommonOptions {
 	var common = CommonOptions{
 		Force:             util.GetBoolFlag(cmd, "force", Force),
@@ -51,6 +118,12 @@ func GetCommonOptions(cmd *cobra.Command) CommonOptions {
 		NoIndex:           util.GetBoolFlag(cmd, "no-index", NoIndex),
 		NoExt:             util.GetBoolFlag(cmd, "no-ext", NoExt),
 		NoMkdir:           util.GetBoolFlag(cmd, "no-mkdir", NoMkdir),
+		To:                util.GetStringArrayFlag(cmd, "to", To),
+		Where:             util.GetStringFlag(cmd, "where", nil, Where),
+		Portable:          util.GetStringFlag(cmd, "portable", nil, Portable),
+		Normalize:         util.GetStringFlag(cmd, "normalize", nil, Normalize),
+		TimeZone:          util.GetStringFlag(cmd, "tz", nil, TimeZone),
+		Verify:            util.GetBoolFlag(cmd, "verify", Verify),
 	}
 	return common
 }
@@ -70,3 +143,139 @@ func GetLinkOptions(cmd *cobra.Command) LinkOptions {
 	}
 	return opts
 }
+
+type FindOptions struct {
+	CommonOptions
+	Format    string
+	Null      bool
+	Recursive bool
+}
+
+func GetFindOptions(cmd *cobra.Command) FindOptions {
+	return FindOptions{
+		CommonOptions: GetCommonOptions(cmd),
+		Format:        util.GetStringFlag(cmd, "format", nil, Format),
+		Null:          util.GetBoolFlag(cmd, "null", Null),
+		Recursive:     util.GetBoolFlag(cmd, "recursive", Recursive),
+	}
+}
+
+type VarsOptions struct {
+	Template string
+	JSON     bool
+	TimeZone string
+}
+
+func GetVarsOptions(cmd *cobra.Command) VarsOptions {
+	return VarsOptions{
+		Template: util.GetStringFlag(cmd, "template", nil, Template),
+		JSON:     util.GetBoolFlag(cmd, "json", JSON),
+		TimeZone: util.GetStringFlag(cmd, "tz", nil, TimeZone),
+	}
+}
+
+type DedupeOptions struct {
+	Recursive bool
+	Strategy  string
+	Keep      string
+	Action    string
+	Force     bool
+	Simulate  bool
+	NoCache   bool
+}
+
+// ValidateStrategy rejects unknown --strategy values instead of silently keeping the oldest file
+func ValidateStrategy(strategy string) error {
+	if util.IndexOf(strategy, AllowedStrategies) == -1 {
+		return errors.New("'" + strategy + "' must be one of " + strings.Join(AllowedStrategies, ", "))
+	}
+	return nil
+}
+
+func GetDedupeOptions(cmd *cobra.Command) DedupeOptions {
+	return DedupeOptions{
+		Recursive: util.GetBoolFlag(cmd, "recursive", Recursive),
+		Strategy:  util.GetStringFlag(cmd, "strategy", nil, Strategy),
+		Keep:      util.GetStringFlag(cmd, "keep", nil, Keep),
+		Action:    util.GetStringFlag(cmd, "action", AllowedActions, Action),
+		Force:     util.GetBoolFlag(cmd, "force", Force),
+		Simulate:  util.GetBoolFlag(cmd, "simulate", Simulate),
+		NoCache:   util.GetBoolFlag(cmd, "no-cache", NoCache),
+	}
+}
+
+type RemoveOptions struct {
+	Recursive bool
+	Permanent bool
+	Force     bool
+	Simulate  bool
+	Verbose   bool
+}
+
+func GetRemoveOptions(cmd *cobra.Command) RemoveOptions {
+	return RemoveOptions{
+		Recursive: util.GetBoolFlag(cmd, "recursive", Recursive),
+		Permanent: util.GetBoolFlag(cmd, "permanent", Permanent),
+		Force:     util.GetBoolFlag(cmd, "force", Force),
+		Simulate:  util.GetBoolFlag(cmd, "simulate", Simulate),
+		Verbose:   util.GetBoolFlag(cmd, "verbose", Verbose),
+	}
+}
+
+type CreateOptions struct {
+	NamesFile string
+	Range     string
+	Vars      []string
+	Portable  string
+	TimeZone  string
+	Simulate  bool
+	Verbose   bool
+}
+
+func GetCreateOptions(cmd *cobra.Command) CreateOptions {
+	return CreateOptions{
+		NamesFile: util.GetStringFlag(cmd, "names", nil, NamesFile),
+		Range:     util.GetStringFlag(cmd, "range", nil, Range),
+		Vars:      util.GetStringArrayFlag(cmd, "var", Vars),
+		Portable:  util.GetStringFlag(cmd, "portable", nil, Portable),
+		TimeZone:  util.GetStringFlag(cmd, "tz", nil, TimeZone),
+		Simulate:  util.GetBoolFlag(cmd, "simulate", Simulate),
+		Verbose:   util.GetBoolFlag(cmd, "verbose", Verbose),
+	}
+}
+
+type ManifestOptions struct {
+	Algorithm string
+	Manifest  string
+	JSON      bool
+	Quiet     bool
+}
+
+func GetManifestOptions(cmd *cobra.Command) ManifestOptions {
+	return ManifestOptions{
+		Algorithm: util.GetStringFlag(cmd, "algo", nil, Algorithm),
+		Manifest:  util.GetStringFlag(cmd, "manifest", nil, Manifest),
+		JSON:      util.GetBoolFlag(cmd, "json", JSON),
+		Quiet:     util.GetBoolFlag(cmd, "quiet", Quiet),
+	}
+}
+
+func GetHashOptions(cmd *cobra.Command) HashOptions {
+	var opts = HashOptions{
+		Algorithm:       util.GetStringFlag(cmd, "algo", nil, Algorithm),
+		AlgorithmForced: cmd.Flags().Changed("algo"),
+		Recursive:       util.GetBoolFlag(cmd, "recursive", Recursive),
+		BSD:             util.GetBoolFlag(cmd, "bsd", BSD),
+		JSON:            util.GetBoolFlag(cmd, "json", JSON),
+		Check:           util.GetBoolFlag(cmd, "check", Check),
+		Quiet:           util.GetBoolFlag(cmd, "quiet", Quiet),
+		NoCache:         util.GetBoolFlag(cmd, "no-cache", NoCache),
+		Benchmark:       util.GetBoolFlag(cmd, "benchmark", Benchmark),
+	}
+	// the command alias picks the algorithm
+	if _, isAlias := hashAliases[cmd.CalledAs()]; isAlias {
+		opts.Algorithm = cmd.CalledAs()
+		opts.AlgorithmForced = true
+	}
+	return opts
+}
@@ -0,0 +1,111 @@
+package snippets
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/flosch/pongo2/v4"
+	"github.com/mitchellh/go-homedir"
+	"github.com/pterm/pterm"
+	"github.com/spf13/viper"
+)
+
+type Snippet struct {
+	Name     string
+	Source   string
+	Template string
+}
+
+type SnippetList []Snippet
+
+var (
+	set      *pongo2.TemplateSet
+	snippets SnippetList
+	loadOnce sync.Once
+)
+
+// snippetLoader lets pongo2's include, import and extends tags resolve snippets by name
+type snippetLoader struct{}
+
+func (l snippetLoader) Abs(base, name string) string {
+	return strings.TrimSuffix(name, ".tpl")
+}
+
+func (l snippetLoader) Get(path string) (io.Reader, error) {
+	for _, s := range snippets {
+		if s.Name == path {
+			return strings.NewReader(s.Template), nil
+		}
+	}
+	return nil, errors.New("template snippet " + path + " not found")
+}
+
+// load reads snippets from ~/.fu/templates/*.tpl and the templates section of the config,
+// it is deferred until first use so the config file has already been read
+func load() {
+	loadOnce.Do(func() {
+		found := map[string]Snippet{}
+		home, err := homedir.Dir()
+		if err == nil {
+			files, _ := filepath.Glob(filepath.Join(home, ".fu", "templates", "*.tpl"))
+			for _, f := range files {
+				content, err := os.ReadFile(f)
+				if err != nil {
+					pterm.Warning.Println(err.Error())
+					continue
+				}
+				name := strings.TrimSuffix(filepath.Base(f), ".tpl")
+				// editors end files with a newline, which would end up in file names
+				found[name] = Snippet{Name: name, Source: f, Template: strings.TrimRight(string(content), " \t\r\n")}
+			}
+		}
+		// snippets in the config file take precedence over files
+		for name, content := range viper.GetStringMapString("templates") {
+			found[name] = Snippet{Name: name, Source: viper.ConfigFileUsed(), Template: content}
+		}
+		for _, s := range found {
+			snippets = append(snippets, s)
+		}
+		sort.Slice(snippets, func(i, j int) bool { return snippets[i].Name < snippets[j].Name })
+		set = pongo2.NewSet("snippets", snippetLoader{})
+	})
+}
+
+func GetSnippets() SnippetList {
+	load()
+	return snippets
+}
+
+// FromString compiles a template that can include, import and use snippets
+func FromString(tpl string) (*pongo2.Template, error) {
+	load()
+	return set.FromString(tpl)
+}
+
+// Use renders the named snippet with the calling template's context, available as use("name")
+func Use(ctx *pongo2.ExecutionContext, name string) (*pongo2.Value, error) {
+	load()
+	tpl, err := set.FromCache(name)
+	if err != nil {
+		return nil, err
+	}
+	out, err := tpl.Execute(ctx.Public)
+	if err != nil {
+		return nil, err
+	}
+	return pongo2.AsSafeValue(out), nil
+}
+
+func (s SnippetList) ToTableData() pterm.TableData {
+	ret := [][]string{}
+	ret = append(ret, []string{"Name", "Source", "Template"})
+	for _, snippet := range s {
+		ret = append(ret, []string{snippet.Name, snippet.Source, strings.TrimSpace(snippet.Template)})
+	}
+	return ret
+}
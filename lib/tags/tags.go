@@ -0,0 +1,26 @@
+package tags
+
+import (
+	"github.com/flosch/pongo2/v4"
+)
+
+// SkipMarker is written by the skip tag, any rendered output containing it is dropped from the batch
+const SkipMarker = "--SKIPFILE--"
+
+func init() {
+	pongo2.RegisterTag("skip", skipTagParser)
+}
+
+type skipTagNode struct{}
+
+func (node *skipTagNode) Execute(ctx *pongo2.ExecutionContext, writer pongo2.TemplateWriter) *pongo2.Error {
+	writer.WriteString(SkipMarker)
+	return nil
+}
+
+func skipTagParser(doc *pongo2.Parser, start *pongo2.Token, arguments *pongo2.Parser) (pongo2.INodeTag, *pongo2.Error) {
+	if arguments.Remaining() > 0 {
+		return nil, arguments.Error("Malformed skip-tag, no arguments allowed.", nil)
+	}
+	return &skipTagNode{}, nil
+}
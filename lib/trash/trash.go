@@ -0,0 +1,113 @@
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Trash moves a file or directory into the trash following the freedesktop.org Trash specification
+// (https://specifications.freedesktop.org/trash-spec/trashspec-latest.html) and returns where it was moved to
+func Trash(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(abs); err != nil {
+		return "", err
+	}
+	trashDir, topDir, err := trashDirFor(abs)
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range []string{filepath.Join(trashDir, "files"), filepath.Join(trashDir, "info")} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return "", err
+		}
+	}
+	// trashes on other mounts store paths relative to the top of the mount so they survive a different mount point
+	infoPath := abs
+	if topDir != "" {
+		if infoPath, err = filepath.Rel(topDir, abs); err != nil {
+			return "", err
+		}
+	}
+	name, info, err := createInfoFile(trashDir, filepath.Base(abs))
+	if err != nil {
+		return "", err
+	}
+	_, err = fmt.Fprintf(info, "[Trash Info]\nPath=%s\nDeletionDate=%s\n", encodePath(infoPath), time.Now().Format("2006-01-02T15:04:05"))
+	info.Close()
+	infoFile := filepath.Join(trashDir, "info", name+".trashinfo")
+	if err != nil {
+		os.Remove(infoFile)
+		return "", err
+	}
+	trashed := filepath.Join(trashDir, "files", name)
+	if err := os.Rename(abs, trashed); err != nil {
+		os.Remove(infoFile)
+		return "", err
+	}
+	return trashed, nil
+}
+
+// Restore moves a trashed file back to its original path and removes its .trashinfo file
+func Restore(trashed string, original string) error {
+	if _, err := os.Lstat(original); err == nil {
+		return errors.New(original + " already exists")
+	}
+	if err := os.MkdirAll(filepath.Dir(original), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(trashed, original); err != nil {
+		return err
+	}
+	info := filepath.Join(filepath.Dir(filepath.Dir(trashed)), "info", filepath.Base(trashed)+".trashinfo")
+	if err := os.Remove(info); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HomeTrash is $XDG_DATA_HOME/Trash, usually ~/.local/share/Trash
+func HomeTrash() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "Trash"), nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "Trash"), nil
+}
+
+// createInfoFile reserves a unique name in the trash by atomically creating its .trashinfo file
+func createInfoFile(trashDir string, base string) (string, *os.File, error) {
+	name := base
+	for i := 2; ; i++ {
+		info, err := os.OpenFile(filepath.Join(trashDir, "info", name+".trashinfo"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			if _, err := os.Lstat(filepath.Join(trashDir, "files", name)); err == nil { // left behind by another program
+				info.Close()
+				os.Remove(info.Name())
+			} else {
+				return name, info, nil
+			}
+		} else if !os.IsExist(err) {
+			return "", nil, err
+		}
+		name = fmt.Sprintf("%s.%d", base, i)
+	}
+}
+
+// encodePath percent-encodes a path like a URL path, slashes are kept
+func encodePath(path string) string {
+	encoded := (&url.URL{Path: filepath.ToSlash(path)}).EscapedPath()
+	return strings.ReplaceAll(encoded, "%2F", "/")
+}
@@ -0,0 +1,72 @@
+//go:build !windows
+// +build !windows
+
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// trashDirFor picks the home trash when the path is on the same device, otherwise the trash at the top of the path's
+// mount, $topdir/.Trash/$uid when an administrator created a sticky .Trash directory or $topdir/.Trash-$uid.
+// topDir is empty for the home trash.
+func trashDirFor(path string) (trashDir string, topDir string, err error) {
+	home, err := HomeTrash()
+	if err != nil {
+		return "", "", err
+	}
+	device, err := deviceOf(path)
+	if err != nil {
+		return "", "", err
+	}
+	if homeDevice, err := deviceOf(existingParent(home)); err == nil && homeDevice == device {
+		return home, "", nil
+	}
+	topDir = filepath.Dir(path)
+	for topDir != filepath.Dir(topDir) {
+		parentDevice, err := deviceOf(filepath.Dir(topDir))
+		if err != nil || parentDevice != device {
+			break
+		}
+		topDir = filepath.Dir(topDir)
+	}
+	uid := os.Getuid()
+	shared := filepath.Join(topDir, ".Trash")
+	if stats, err := os.Lstat(shared); err == nil && stats.IsDir() && stats.Mode()&os.ModeSticky != 0 {
+		trashDir = filepath.Join(shared, fmt.Sprintf("%d", uid))
+		if err := os.MkdirAll(trashDir, 0700); err == nil {
+			return trashDir, topDir, nil
+		}
+	}
+	trashDir = filepath.Join(topDir, fmt.Sprintf(".Trash-%d", uid))
+	if err := os.MkdirAll(trashDir, 0700); err != nil {
+		return "", "", errors.New("could not create a trash on the same device as " + path + ", use --permanent to delete it instead")
+	}
+	return trashDir, topDir, nil
+}
+
+func deviceOf(path string) (uint64, error) {
+	stats, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	sys, ok := stats.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, errors.New("could not read the device of " + path)
+	}
+	return uint64(sys.Dev), nil
+}
+
+// existingParent walks up until it finds a path that exists, the home trash may not have been created yet
+func existingParent(path string) string {
+	for {
+		if _, err := os.Lstat(path); err == nil || path == filepath.Dir(path) {
+			return path
+		}
+		path = filepath.Dir(path)
+	}
+}
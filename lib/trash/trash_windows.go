@@ -0,0 +1,8 @@
+package trash
+
+import "errors"
+
+// trashDirFor fails because the freedesktop.org trash isn't used on Windows and the Recycle Bin isn't supported yet
+func trashDirFor(path string) (string, string, error) {
+	return "", "", errors.New("the trash is not supported on Windows, use --permanent to delete " + path)
+}
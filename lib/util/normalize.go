@@ -0,0 +1,119 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var NormalizationForms = []string{"nfc", "nfd"}
+
+// Normalize converts a string to the given unicode normalization form, an unknown form returns it unchanged
+func Normalize(s string, form string) string {
+	switch form {
+	case "nfc":
+		return norm.NFC.String(s)
+	case "nfd":
+		return norm.NFD.String(s)
+	}
+	return s
+}
+
+// NormalizePath normalizes the file name and any directories of an absolute path that do not exist yet
+func NormalizePath(path string, form string) string {
+	ret, _ := mapNewComponents(path, func(part string) (string, error) {
+		return Normalize(part, form), nil
+	})
+	return ret
+}
+
+// PathExists checks if a path exists, when a normalization form is given a file whose
+// name only differs in normalization counts as existing
+func PathExists(path string, form string) bool {
+	_, err := os.Lstat(path)
+	if err == nil {
+		return true
+	}
+	if form == "" {
+		return false
+	}
+	base := Normalize(filepath.Base(path), form)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if Normalize(e.Name(), form) == base {
+			return true
+		}
+	}
+	return false
+}
+
+// Glob works like filepath.Glob, when a normalization form is given names are compared
+// in that form so NFC patterns match NFD files and vice versa
+func Glob(pattern string, form string) ([]string, error) {
+	if form == "" {
+		return filepath.Glob(pattern)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	root := filepath.VolumeName(pattern)
+	rest := pattern[len(root):]
+	if len(rest) > 0 && os.IsPathSeparator(rest[0]) {
+		root += string(filepath.Separator)
+	}
+	paths := []string{root}
+	for _, part := range strings.FieldsFunc(rest, isSeparator) {
+		next := []string{}
+		for _, dir := range paths {
+			next = append(next, globComponent(dir, part, form)...)
+		}
+		paths = next
+	}
+	return paths, nil
+}
+
+// globComponent matches one component of a pattern against the entries of dir, literal components
+// are only compared by normalization when a path with exactly that name doesn't exist
+func globComponent(dir string, part string, form string) []string {
+	if !strings.ContainsAny(part, `*?[\`) {
+		path := joinComponent(dir, part)
+		if _, err := os.Lstat(path); err == nil {
+			return []string{path}
+		}
+		if part == "." || part == ".." {
+			return nil
+		}
+	}
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	part = Normalize(part, form)
+	ret := []string{}
+	for _, e := range entries {
+		if matched, _ := filepath.Match(part, Normalize(e.Name(), form)); matched {
+			ret = append(ret, joinComponent(dir, e.Name()))
+		}
+	}
+	return ret
+}
+
+func isSeparator(r rune) bool {
+	return r == '/' || r == filepath.Separator
+}
+
+func joinComponent(dir string, name string) string {
+	if dir == "" {
+		return name
+	}
+	return filepath.Join(dir, name)
+}
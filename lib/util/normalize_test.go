@@ -0,0 +1,56 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlobNormalized(t *testing.T) {
+	root := t.TempDir()
+	nfd := "cafe\u0301.txt"
+	for _, dir := range []string{"sub", "work"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"a.txt", nfd} {
+		if err := ioutil.WriteFile(filepath.Join(root, "sub", name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cwd, _ := os.Getwd()
+	if err := os.Chdir(filepath.Join(root, "work")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	abs := func(parts ...string) string { return filepath.Join(append([]string{root}, parts...)...) }
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"../sub/*.txt", []string{filepath.Join("..", "sub", "a.txt"), filepath.Join("..", "sub", nfd)}},
+		{"../sub/a.txt", []string{filepath.Join("..", "sub", "a.txt")}},
+		{"../sub/caf\u00e9.txt", []string{filepath.Join("..", "sub", nfd)}},
+		{"./../sub/caf\u00e9.*", []string{filepath.Join("..", "sub", nfd)}},
+		{"../sub/../sub/a.txt", []string{filepath.Join("..", "sub", "a.txt")}},
+		{"../missing/*.txt", []string{}},
+		{"../sub/missing.txt", []string{}},
+		{abs("sub", "*.txt"), []string{abs("sub", "a.txt"), abs("sub", nfd)}},
+		{abs("work", "..", "sub", "caf\u00e9.txt"), []string{abs("sub", nfd)}},
+		{abs("s*", "a.txt"), []string{abs("sub", "a.txt")}},
+	}
+	for _, tt := range tests {
+		got, err := Glob(tt.pattern, "nfc")
+		if err != nil {
+			t.Errorf("Glob(%q) error = %v", tt.pattern, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Glob(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
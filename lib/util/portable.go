@@ -0,0 +1,116 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const MaxComponentBytes = 255
+
+var (
+	PortablePlatforms    = []string{"posix", "windows", "macos"}
+	invalidChars         = map[string]string{"posix": "/", "macos": "/:", "windows": "<>:\"/\\|?*"}
+	windowsReservedNames = []string{"CON", "PRN", "AUX", "NUL",
+		"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+		"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9"}
+)
+
+// SanitizeComponent cleans a single file or directory name so it is valid on the given platform,
+// it returns the cleaned name and a description of every substitution made
+func SanitizeComponent(name string, platform string) (string, []string) {
+	changes := []string{}
+	chars, found := invalidChars[platform]
+	if !found {
+		return name, changes
+	}
+	var sb strings.Builder
+	for _, r := range name {
+		if r == 0 || strings.ContainsRune(chars, r) || (platform == "windows" && r < 32) {
+			changes = append(changes, fmt.Sprintf("replaced %q with \"_\"", r))
+			sb.WriteRune('_')
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	ret := sb.String()
+	if platform == "windows" {
+		trimmed := strings.TrimRight(ret, ". ")
+		if trimmed != ret {
+			changes = append(changes, fmt.Sprintf("removed trailing %q", ret[len(trimmed):]))
+			ret = trimmed
+		}
+		base := strings.SplitN(ret, ".", 2)
+		if IndexOf(strings.ToUpper(base[0]), windowsReservedNames) > -1 {
+			changes = append(changes, fmt.Sprintf("renamed reserved name %q", base[0]))
+			base[0] = base[0] + "_"
+			ret = strings.Join(base, ".")
+		}
+	}
+	if len(ret) > MaxComponentBytes {
+		ret = truncateComponent(ret, MaxComponentBytes)
+		changes = append(changes, fmt.Sprintf("truncated to %d bytes", MaxComponentBytes))
+	}
+	return ret, changes
+}
+
+// SanitizePath cleans the file name and any directories of an absolute path that do not exist yet,
+// a component that can't be cleaned into a usable name is rejected with an error
+func SanitizePath(path string, platform string) (string, []string, error) {
+	changes := []string{}
+	ret, err := mapNewComponents(path, func(part string) (string, error) {
+		cleaned, partChanges := SanitizeComponent(part, platform)
+		if strings.Trim(cleaned, ".") == "" {
+			return part, errors.New(part + " is not a valid " + platform + " file name")
+		}
+		for _, c := range partChanges {
+			changes = append(changes, fmt.Sprintf("%s: %s", part, c))
+		}
+		return cleaned, nil
+	})
+	return ret, changes, err
+}
+
+// mapNewComponents applies fn to the file name and every directory of an absolute path after the
+// first one that doesn't exist on disk, existing directories are left alone so they aren't duplicated
+func mapNewComponents(path string, fn func(string) (string, error)) (string, error) {
+	current := filepath.VolumeName(path) + string(os.PathSeparator)
+	exists := true
+	parts := strings.Split(strings.TrimPrefix(path, current), string(os.PathSeparator))
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if exists && i < len(parts)-1 {
+			if _, err := os.Stat(filepath.Join(current, part)); err == nil {
+				current = filepath.Join(current, part)
+				continue
+			}
+			exists = false
+		}
+		mapped, err := fn(part)
+		if err != nil {
+			return path, err
+		}
+		current = filepath.Join(current, mapped)
+	}
+	return current, nil
+}
+
+// truncateComponent shortens a name to a number of bytes, keeping the extension and never splitting a character
+func truncateComponent(name string, limit int) string {
+	ext := filepath.Ext(name)
+	if len(ext) >= limit {
+		ext = ""
+	}
+	base := strings.TrimSuffix(name, ext)
+	max := limit - len(ext)
+	for len(base) > max {
+		_, size := utf8.DecodeLastRuneInString(base)
+		base = base[:len(base)-size]
+	}
+	return base + ext
+}
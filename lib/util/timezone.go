@@ -0,0 +1,18 @@
+package util
+
+import (
+	"time"
+	_ "time/tzdata" // time zones for --tz on systems without a zoneinfo database
+)
+
+// LoadTimeZone finds an IANA time zone such as America/New_York, an empty name is the local time zone
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local, err
+	}
+	return loc, nil
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"runtime"
@@ -68,6 +69,14 @@ func GetIntFlag(cmd *cobra.Command, name string, allowedValues []int, defaultVal
 	return defaultValue
 }
 
+func GetStringArrayFlag(cmd *cobra.Command, name string, defaultValue []string) []string {
+	ret, err := cmd.Flags().GetStringArray(name)
+	if err != nil {
+		return defaultValue
+	}
+	return ret
+}
+
 func IndexOf(word string, data []string) int {
 	for k, v := range data {
 		if word == v {
@@ -115,3 +124,21 @@ func RunCommand(args []string, cwd string) error {
 	}
 	return cmd.Run()
 }
+
+// FormatBytes formats a size with SI (kB, MB) or, with iec, binary (KiB, MiB) units
+func FormatBytes(size int64, iec bool) string {
+	unit, prefixes, suffix := 1000.0, "kMGTPE", "B"
+	if iec {
+		unit, prefixes, suffix = 1024.0, "KMGTPE", "iB"
+	}
+	value := float64(size)
+	if math.Abs(value) < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	exp := 0
+	for math.Abs(value) >= unit && exp < len(prefixes) {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %c%s", value, prefixes[exp-1], suffix)
+}